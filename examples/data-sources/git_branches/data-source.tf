@@ -0,0 +1,3 @@
+data "git_branches" "this" {
+  prefix = "env/"
+}
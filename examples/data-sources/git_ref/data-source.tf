@@ -0,0 +1,11 @@
+data "git_ref" "release" {
+  branch = "release/v1"
+}
+
+resource "git_repository_file" "this" {
+  count = data.git_ref.release.exists ? 1 : 0
+
+  branch  = "release/v1"
+  path    = "VERSION"
+  content = "v1"
+}
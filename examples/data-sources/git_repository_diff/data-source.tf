@@ -0,0 +1,5 @@
+data "git_repository_diff" "this" {
+  path = "apps/podinfo"
+  base = "prod"
+  head = "staging"
+}
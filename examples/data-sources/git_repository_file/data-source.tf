@@ -0,0 +1,4 @@
+data "git_repository_file" "this" {
+  path   = "README.md"
+  commit = "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"
+}
@@ -0,0 +1,19 @@
+data "git_repository_files" "apps" {
+  branch = "main"
+  path   = "apps"
+}
+
+# Import blocks with for_each require Terraform 1.7 or later.
+import {
+  for_each = toset(data.git_repository_files.apps.files)
+  to       = git_repository_file.apps[each.value]
+  id       = "main:${each.value}"
+}
+
+resource "git_repository_file" "apps" {
+  for_each = toset(data.git_repository_files.apps.files)
+
+  branch  = "main"
+  path    = each.value
+  content = file("${path.module}/${each.value}")
+}
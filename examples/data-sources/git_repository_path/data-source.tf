@@ -0,0 +1,8 @@
+data "git_repository_path" "app" {
+  path = "/clusters/${var.cluster}/${var.app}.yaml"
+}
+
+resource "git_repository_file" "app" {
+  path    = data.git_repository_path.app.safe
+  content = var.manifest
+}
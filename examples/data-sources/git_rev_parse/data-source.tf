@@ -0,0 +1,3 @@
+data "git_rev_parse" "previous" {
+  revision = "HEAD~1"
+}
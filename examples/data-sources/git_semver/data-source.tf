@@ -0,0 +1,9 @@
+data "git_semver" "chart" {
+  versions   = ["v1.2.0", "v1.10.0", "v1.9.3", "v2.0.0-rc.1", "latest"]
+  constraint = "^1.2"
+}
+
+resource "git_repository_file" "version" {
+  path    = "VERSION"
+  content = data.git_semver.chart.highest
+}
@@ -0,0 +1,13 @@
+data "git_ssh_known_hosts_entry" "github" {
+  host       = "github.com"
+  public_key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+}
+
+provider "git" {
+  url = "ssh://git@github.com/example/repo.git"
+  ssh = {
+    username    = "git"
+    private_key = var.private_key
+    known_hosts = data.git_ssh_known_hosts_entry.github.line
+  }
+}
@@ -0,0 +1,4 @@
+resource "git_attributes_entry" "this" {
+  pattern    = "*.sh"
+  attributes = ["text", "eol=lf"]
+}
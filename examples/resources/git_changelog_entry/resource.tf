@@ -0,0 +1,4 @@
+resource "git_changelog_entry" "this" {
+  section = "Added"
+  entry   = "Podinfo is deployed to the dev cluster."
+}
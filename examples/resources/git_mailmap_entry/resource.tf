@@ -0,0 +1,5 @@
+resource "git_mailmap_entry" "this" {
+  proper_name  = "Jane Doe"
+  proper_email = "jane.doe@example.com"
+  commit_email = "jane@old-domain.example.com"
+}
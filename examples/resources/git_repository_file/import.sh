@@ -1 +1,17 @@
-terraform import git_repository_fike.this main:README.md
\ No newline at end of file
+terraform import git_repository_file.this main:README.md
+
+# The file can be imported at a commit, tag or branch, which is recorded as the
+# version of the file last written by Terraform.
+terraform import git_repository_file.this main:README.md@v1.0.0
+
+# Text after the last @ is only used as the ref when it is a full commit SHA or
+# an existing tag or branch, so paths containing @ can be imported as is.
+terraform import git_repository_file.this main:packages/@scope/pkg.json
+
+# A trailing @ imports a path which ends with @ and the name of a tag or branch
+# at the head of the branch.
+terraform import git_repository_file.this main:charts/app@v1.0.0@
+
+# The ID can also be a JSON object, which takes the path and the optional ref
+# as they are without splitting them.
+terraform import git_repository_file.this '{"branch": "main", "path": "charts/app@v1.0.0", "ref": "v1.0.0"}'
@@ -0,0 +1,4 @@
+resource "git_repository_file_absent" "this" {
+  path    = "apps/legacy/deployment.yaml"
+  message = "Remove deprecated legacy deployment"
+}
@@ -0,0 +1,10 @@
+resource "git_repository_template" "this" {
+  path       = "clusters/dev"
+  source_dir = "${path.module}/templates/cluster"
+  vars = {
+    name        = "dev"
+    environment = "development"
+  }
+  exclude = ["*.bak", ".terraform/"]
+  message = "Render dev cluster"
+}
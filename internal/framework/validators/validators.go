@@ -3,7 +3,11 @@ package validators
 import (
 	"context"
 	"fmt"
+	"net/mail"
 	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
@@ -88,3 +92,225 @@ OUTER:
 func MustContain(contains ...string) validator.Set {
 	return mustContainValidator{contains: contains}
 }
+
+type oneOfValidator struct {
+	values []string
+}
+
+func (v oneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of %v", v.values)
+}
+
+func (v oneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of %v", v.values)
+}
+
+func (v oneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	for _, value := range v.values {
+		if value == req.ConfigValue.ValueString() {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Value",
+		fmt.Sprintf("Value must be one of %v", v.values),
+	)
+}
+
+func OneOf(values ...string) validator.String {
+	return oneOfValidator{values: values}
+}
+
+type relativePathValidator struct{}
+
+func (v relativePathValidator) Description(ctx context.Context) string {
+	return "path must be relative and normalized"
+}
+
+func (v relativePathValidator) MarkdownDescription(ctx context.Context) string {
+	return "path must be relative and normalized"
+}
+
+func (v relativePathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	// Windows separators are converted to forward slashes when writing the file.
+	p := strings.ReplaceAll(req.ConfigValue.ValueString(), `\`, "/")
+	if strings.HasPrefix(p, "/") {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Path", fmt.Sprintf("Path %q has to be relative to the repository root.", p))
+		return
+	}
+	if p == "" || p == "." || path.Clean(p) != p {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Path", fmt.Sprintf("Path %q has to be normalized, for example %q.", p, path.Clean(p)))
+		return
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." || part == ".git" {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid Path", fmt.Sprintf("Path %q cannot contain %q.", p, part))
+			return
+		}
+	}
+}
+
+func RelativePath() validator.String {
+	return relativePathValidator{}
+}
+
+// SanitizePath returns the path cleaned into the form which the RelativePath
+// validator accepts, together with why it cannot be written to when it is
+// outside of the repository, the repository root or in the .git directory.
+// The reason is empty when the path is valid.
+func SanitizePath(p string) (string, string) {
+	clean := path.Clean(strings.TrimLeft(strings.ReplaceAll(p, `\`, "/"), "/"))
+	if clean == "." {
+		return "", "refers to the repository root"
+	}
+	for _, part := range strings.Split(clean, "/") {
+		if part == ".." {
+			return "", "is outside of the repository"
+		}
+		if part == ".git" {
+			return "", `cannot contain ".git"`
+		}
+	}
+	return clean, ""
+}
+
+type branchNameValidator struct{}
+
+func (v branchNameValidator) Description(ctx context.Context) string {
+	return "value must be a valid branch name"
+}
+
+func (v branchNameValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid branch name"
+}
+
+func (v branchNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	name := req.ConfigValue.ValueString()
+	if reason := invalidBranchName(name); reason != "" {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Branch Name", fmt.Sprintf("Branch name %q is not valid, as it %s.", name, reason))
+	}
+}
+
+// invalidBranchName returns why the name is not a valid branch name according
+// to the rules of git check-ref-format, or an empty string when it is valid.
+func invalidBranchName(name string) string {
+	switch {
+	case name == "" || name == "@":
+		return "is not a name"
+	case strings.HasPrefix(name, "-"):
+		return "starts with -"
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.Contains(name, "//"):
+		return "has an empty path component"
+	case strings.HasSuffix(name, "."):
+		return "ends with ."
+	case strings.Contains(name, ".."):
+		return "contains .."
+	case strings.Contains(name, "@{"):
+		return "contains @{"
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return fmt.Sprintf("contains %q", r)
+		}
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return "has a path component starting with . or ending with .lock"
+		}
+	}
+	return ""
+}
+
+func BranchName() validator.String {
+	return branchNameValidator{}
+}
+
+type emailValidator struct{}
+
+func (v emailValidator) Description(ctx context.Context) string {
+	return "value must be an email address"
+}
+
+func (v emailValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be an email address"
+}
+
+func (v emailValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	addr, err := mail.ParseAddress(req.ConfigValue.ValueString())
+	if err != nil || addr.Name != "" || addr.Address != req.ConfigValue.ValueString() {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Email", fmt.Sprintf("Value %q is not an email address such as user@example.com.", req.ConfigValue.ValueString()))
+	}
+}
+
+func Email() validator.String {
+	return emailValidator{}
+}
+
+type singleLineValidator struct{}
+
+func (v singleLineValidator) Description(ctx context.Context) string {
+	return "value cannot contain newlines"
+}
+
+func (v singleLineValidator) MarkdownDescription(ctx context.Context) string {
+	return "value cannot contain newlines"
+}
+
+func (v singleLineValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	if strings.ContainsAny(req.ConfigValue.ValueString(), "\r\n") {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Value", fmt.Sprintf("Value %q cannot contain newlines.", req.ConfigValue.ValueString()))
+	}
+}
+
+func SingleLine() validator.String {
+	return singleLineValidator{}
+}
+
+type textContentValidator struct{}
+
+func (v textContentValidator) Description(ctx context.Context) string {
+	return "value must be valid UTF-8 text without NUL bytes"
+}
+
+func (v textContentValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be valid UTF-8 text without NUL bytes"
+}
+
+// ValidateString rejects binary content, which is corrupted when it is passed
+// through a string as Terraform strings cannot hold arbitrary bytes. The value is
+// not included in the diagnostic, as it may be sensitive.
+func (v textContentValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	s := req.ConfigValue.ValueString()
+	reason := ""
+	if i := strings.IndexByte(s, 0); i != -1 {
+		reason = fmt.Sprintf("a NUL byte at offset %d", i)
+	} else if !utf8.ValidString(s) {
+		reason = "invalid UTF-8"
+	}
+	if reason != "" {
+		resp.Diagnostics.AddAttributeError(req.Path, "Binary Content", fmt.Sprintf("Value contains %s, and is likely binary content which cannot be written without corrupting it. Set content_base64 to the base64 encoded content instead, for example with filebase64() rather than base64decode().", reason))
+	}
+}
+
+func TextContent() validator.String {
+	return textContentValidator{}
+}
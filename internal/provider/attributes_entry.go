@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type AttributesEntryResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Branch      types.String   `tfsdk:"branch"`
+	Path        types.String   `tfsdk:"path"`
+	Pattern     types.String   `tfsdk:"pattern"`
+	Attributes  types.List     `tfsdk:"attributes"`
+	CommitSHA   types.String   `tfsdk:"commit_sha"`
+	AuthorName  types.String   `tfsdk:"author_name"`
+	AuthorEmail types.String   `tfsdk:"author_email"`
+	Message     types.String   `tfsdk:"message"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+var _ resource.Resource = &AttributesEntryResource{}
+var _ resource.ResourceWithModifyPlan = &AttributesEntryResource{}
+var _ resource.ResourceWithValidateConfig = &AttributesEntryResource{}
+
+func NewAttributesEntryResource() resource.Resource {
+	return &AttributesEntryResource{}
+}
+
+type AttributesEntryResource struct {
+	prd *ProviderResourceData
+}
+
+func (r *AttributesEntryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_attributes_entry"
+}
+
+func (r *AttributesEntryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Attributes entry resource, which adds a line with a pattern and its attributes to a `.gitattributes` file. Only the line is owned by the resource, so that several resources can add attributes to the same file.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch to write the line to. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the `.gitattributes` file relative to the root of the repository, or to the provider path prefix. Files in subdirectories apply to the files below the directory. The file is created when it does not exist. Defaults to `.gitattributes`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultAttributesPath),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"pattern": schema.StringAttribute{
+				Description: "Pattern of the files which the attributes apply to, for example `*.sh` or `assets/**`.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"attributes": schema.ListAttribute{
+				Description: "Attributes of the files matching the pattern, for example `eol=lf`, `filter=lfs` or `-text`. The line is added again when it is removed from the file outside of Terraform.",
+				ElementType: types.StringType,
+				Required:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"commit_sha": schema.StringAttribute{
+				Description: "SHA of the commit which added the line, or of the head commit of the branch when the file already contained the line.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"author_name": schema.StringAttribute{
+				Description: "Author name of the commit. Defaults to the provider commits author name.",
+				Optional:    true,
+			},
+			"author_email": schema.StringAttribute{
+				Description: "Author email of the commit. Defaults to the provider commits author email.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"message": schema.StringAttribute{
+				Description: "Commit message. Defaults to the provider commits message.",
+				Optional:    true,
+			},
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+func (r *AttributesEntryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+func (r *AttributesEntryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AttributesEntryResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if p := data.Path.ValueString(); !data.Path.IsNull() && !data.Path.IsUnknown() && p != defaultAttributesPath && !strings.HasSuffix(p, "/"+defaultAttributesPath) {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Invalid Path", fmt.Sprintf("Path %q has to be a %s file, as other files are not read by git.", p, defaultAttributesPath))
+	}
+	if pattern := data.Pattern.ValueString(); strings.ContainsAny(pattern, " \t\r\n") || strings.HasPrefix(pattern, "#") || strings.HasPrefix(pattern, "!") {
+		resp.Diagnostics.AddAttributeError(path.Root("pattern"), "Invalid Pattern", fmt.Sprintf("Pattern %q cannot contain whitespace, or start with # or !.", pattern))
+	}
+	if data.Attributes.IsUnknown() {
+		return
+	}
+	if len(data.Attributes.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("attributes"), "Missing Attributes", "At least one attribute has to be set.")
+	}
+	for i, v := range data.Attributes.Elements() {
+		s, ok := v.(types.String)
+		if !ok || s.IsUnknown() {
+			continue
+		}
+		if !attributeRegex.MatchString(s.ValueString()) {
+			resp.Diagnostics.AddAttributeError(path.Root("attributes").AtListIndex(i), "Invalid Attribute", fmt.Sprintf("Attribute %q has to be a name, optionally prefixed with - or !, or a name and value like name=value.", s.ValueString()))
+		}
+	}
+}
+
+// ModifyPlan checks the branch against the protected branches, and the path
+// against the path policy.
+func (r *AttributesEntryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.prd == nil {
+		return
+	}
+
+	var branch, p types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("branch"), &branch)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &p)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.prd.validateBranch(ctx, branch)...)
+	if !p.IsUnknown() {
+		resp.Diagnostics.Append(r.prd.validatePath(path.Root("path"), r.prd.RepositoryPath(p.ValueString()))...)
+	}
+}
+
+func (r *AttributesEntryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *AttributesEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	branch, err := r.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", r.prd.redact(err.Error()))
+		return
+	}
+	data.Branch = types.StringValue(branch)
+	line, diags := data.line(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	hash, err := r.prd.ApplyChange(ctx, createTimeout, branch, commit, repository.PushConfig{}, fileChange{
+		path: r.prd.RepositoryPath(data.Path.ValueString()),
+		edit: func(content string, exists bool) (string, error) {
+			return addAttributesLine(content, line), nil
+		},
+	})
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Attributes Error", err)
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s:%s", branch, data.Path.ValueString(), line))
+	data.CommitSHA = types.StringValue(hash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read removes the resource from the state when the file does not contain the
+// line, so that it is added again.
+func (r *AttributesEntryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *AttributesEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	line, diags := data.line(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	p := r.prd.RepositoryPath(data.Path.ValueString())
+	b, _, err := r.prd.ReadFile(ctx, data.Branch.ValueString(), p)
+	if errors.Is(err, os.ErrNotExist) {
+		tflog.Warn(ctx, "Removing resource from state as the attributes file does not exist", map[string]interface{}{"path": p})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Attributes Read Error", err)
+		return
+	}
+	if !hasAttributesLine(string(b), line) {
+		tflog.Warn(ctx, "Removing resource from state as the attributes file does not contain the line", map[string]interface{}{"path": p, "line": line})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+}
+
+// Update only changes the commit attributes, as changes to the line replace
+// the resource.
+func (r *AttributesEntryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *AttributesEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AttributesEntryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *AttributesEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	line, diags := data.line(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	_, err := r.prd.ApplyChange(ctx, deleteTimeout, data.Branch.ValueString(), commit, repository.PushConfig{}, fileChange{
+		path: r.prd.RepositoryPath(data.Path.ValueString()),
+		edit: func(content string, exists bool) (string, error) {
+			if !exists {
+				return "", os.ErrNotExist
+			}
+			return removeAttributesLine(content, line), nil
+		},
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Attributes Error", err)
+		return
+	}
+}
+
+func (m *AttributesEntryResourceModel) line(ctx context.Context) (string, diag.Diagnostics) {
+	attributes := []string{}
+	diags := m.Attributes.ElementsAs(ctx, &attributes, false)
+	return attributesLine(m.Pattern.ValueString(), attributes), diags
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// auditRecord is a line of the audit log.
+type auditRecord struct {
+	Time       string   `json:"time"`
+	Run        string   `json:"run"`
+	Operation  string   `json:"operation"`
+	Repository string   `json:"repository"`
+	Branch     string   `json:"branch"`
+	Paths      []string `json:"paths,omitempty"`
+	Commit     string   `json:"commit,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+	Outcome    string   `json:"outcome"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// auditLog appends a JSON record of each git operation to a file. Records of
+// the same Terraform operation share the run id, as the provider is configured
+// once per operation.
+type auditLog struct {
+	path string
+	run  string
+	mu   sync.Mutex
+}
+
+func newAuditLog(p string) (*auditLog, error) {
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	err = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 8)
+	_, err = rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{path: p, run: hex.EncodeToString(b)}, nil
+}
+
+func (a *auditLog) write(record auditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// audit records the outcome of the operation started at the given time in the
+// audit log, when one is configured. Failing to write the record does not fail
+// the operation, as it may already have been pushed.
+func (prd *ProviderResourceData) audit(ctx context.Context, operation, branch string, paths []string, commit string, start time.Time, err error) {
+	if prd.auditLog == nil {
+		return
+	}
+	record := auditRecord{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Run:        prd.auditLog.run,
+		Operation:  operation,
+		Repository: redactURLCredentials(prd.url),
+		Branch:     branch,
+		Paths:      paths,
+		Commit:     commit,
+		DurationMs: time.Since(start).Milliseconds(),
+		Outcome:    "success",
+	}
+	if err != nil {
+		record.Outcome = "failure"
+		record.Error = prd.redact(err.Error())
+	}
+	writeErr := prd.auditLog.write(record)
+	if writeErr != nil {
+		tflog.Error(ctx, "Could not write audit log record", map[string]interface{}{"path": prd.auditLog.path, "error": writeErr.Error()})
+	}
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// defaultBatchWindow is how long file changes are collected before they are committed.
+const defaultBatchWindow = 2 * time.Second
+
+// batchTimeout limits how long committing and pushing a batch can take, as the
+// batch is not bound to the context of a single resource operation.
+const batchTimeout = 10 * time.Minute
+
+// batchKey identifies the changes which can be combined. The resource data of
+// the first change is used for the whole batch, so the key contains everything
+// which resources can change in it.
+type batchKey struct {
+	url         string
+	branch      string
+	baseBranch  string
+	repository  string
+	authorName  string
+	authorEmail string
+	message     string
+	pushConfig  string
+}
+
+type batchResult struct {
+	hash string
+	err  error
+}
+
+type pendingChange struct {
+	change fileChange
+	result chan batchResult
+}
+
+type batch struct {
+	prd        *ProviderResourceData
+	branch     string
+	commit     git.Commit
+	pushConfig repository.PushConfig
+	changes    []pendingChange
+}
+
+// batcher combines the file changes made by concurrent resource operations into
+// a single commit and push. Terraform does not tell providers when an apply
+// ends, so changes are collected for a window after the first change instead.
+type batcher struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	batches map[batchKey]*batch
+}
+
+func newBatcher(window time.Duration) *batcher {
+	return &batcher{
+		window:  window,
+		batches: map[batchKey]*batch{},
+	}
+}
+
+// Submit adds the change to the batch of the repository and branch, and waits
+// until the batch has been pushed. Only changes with the same commit
+// information, push configuration, base branch and repository credentials are
+// combined. A change whose context is done before the batch is flushed is
+// removed from the batch, while the result of a batch which is already being
+// pushed is waited for, so that the result matches the remote repository.
+func (b *batcher) Submit(ctx context.Context, prd *ProviderResourceData, branch string, commit git.Commit, pushConfig repository.PushConfig, change fileChange) (string, error) {
+	key := batchKey{
+		url:         prd.url,
+		branch:      branch,
+		baseBranch:  prd.baseBranch,
+		repository:  fmt.Sprintf("%v %v", prd.ssh, prd.http),
+		authorName:  commit.Author.Name,
+		authorEmail: commit.Author.Email,
+		message:     commit.Message,
+		pushConfig:  fmt.Sprintf("%v %v %v", pushConfig.Refspecs, pushConfig.Force, prd.pushOptions),
+	}
+	pending := pendingChange{
+		change: change,
+		result: make(chan batchResult, 1),
+	}
+
+	b.mu.Lock()
+	bt, ok := b.batches[key]
+	if !ok {
+		bt = &batch{
+			prd:        prd,
+			branch:     branch,
+			commit:     commit,
+			pushConfig: pushConfig,
+		}
+		b.batches[key] = bt
+		time.AfterFunc(b.window, func() {
+			b.flush(key, bt)
+		})
+	}
+	bt.changes = append(bt.changes, pending)
+	b.mu.Unlock()
+
+	select {
+	case result := <-pending.result:
+		return result.hash, result.err
+	case <-ctx.Done():
+	}
+	b.mu.Lock()
+	if b.batches[key] == bt {
+		for i, c := range bt.changes {
+			if c.result == pending.result {
+				bt.changes = append(bt.changes[:i], bt.changes[i+1:]...)
+				break
+			}
+		}
+		b.mu.Unlock()
+		return "", ctx.Err()
+	}
+	b.mu.Unlock()
+	result := <-pending.result
+	return result.hash, result.err
+}
+
+func (b *batcher) flush(key batchKey, bt *batch) {
+	b.mu.Lock()
+	delete(b.batches, key)
+	changes := bt.changes
+	b.mu.Unlock()
+	if len(changes) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchTimeout)
+	defer cancel()
+	fileChanges := []fileChange{}
+	for _, pending := range changes {
+		fileChanges = append(fileChanges, pending.change)
+	}
+	hash, errs := bt.prd.commitChanges(ctx, batchTimeout, bt.branch, bt.commit, bt.pushConfig, fileChanges)
+	for i, pending := range changes {
+		pending.result <- batchResult{hash: hash, err: errs[i]}
+	}
+}
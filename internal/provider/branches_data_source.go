@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type BranchesDataSourceModel struct {
+	ID       types.String  `tfsdk:"id"`
+	Prefix   types.String  `tfsdk:"prefix"`
+	Branches []BranchModel `tfsdk:"branches"`
+}
+
+type BranchModel struct {
+	Name types.String `tfsdk:"name"`
+	SHA  types.String `tfsdk:"sha"`
+}
+
+var _ datasource.DataSource = &BranchesDataSource{}
+
+func NewBranchesDataSource() datasource.DataSource {
+	return &BranchesDataSource{}
+}
+
+type BranchesDataSource struct {
+	prd *ProviderResourceData
+}
+
+func (d *BranchesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branches"
+}
+
+func (d *BranchesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Branches data source",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"prefix": schema.StringAttribute{
+				Description: "Only lists the branches with names starting with the prefix, for example `env/`.",
+				Optional:    true,
+			},
+			"branches": schema.ListNestedAttribute{
+				Description: "Branches of the repository sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the branch.",
+							Computed:    true,
+						},
+						"sha": schema.StringAttribute{
+							Description: "SHA of the head commit of the branch.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *BranchesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.prd = prd
+}
+
+func (d *BranchesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *BranchesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	refs, err := d.prd.ListRefs(ctx)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		d.prd.addGitError(&resp.Diagnostics, "Git Branches Error", err)
+		return
+	}
+	data.Branches = []BranchModel{}
+	for _, ref := range refs {
+		if !ref.Name().IsBranch() || !strings.HasPrefix(ref.Name().Short(), data.Prefix.ValueString()) {
+			continue
+		}
+		data.Branches = append(data.Branches, BranchModel{
+			Name: types.StringValue(ref.Name().Short()),
+			SHA:  types.StringValue(ref.Hash().String()),
+		})
+	}
+	sort.Slice(data.Branches, func(i, j int) bool {
+		return data.Branches[i].Name.ValueString() < data.Branches[j].Name.ValueString()
+	})
+	data.ID = types.StringValue(redactURLCredentials(d.prd.url))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"strings"
+)
+
+const (
+	defaultChangelogPath    = "CHANGELOG.md"
+	defaultChangelogHeading = "## [Unreleased]"
+	changelogTitle          = "# Changelog"
+)
+
+// changelogLine returns the list item of the entry.
+func changelogLine(entry string) string {
+	if strings.HasPrefix(entry, "- ") || strings.HasPrefix(entry, "* ") {
+		return entry
+	}
+	return "- " + entry
+}
+
+// changelogSection returns the heading of the section, which is a level
+// below the release headings of the keep a changelog layout.
+func changelogSection(section string) string {
+	if section == "" || strings.HasPrefix(section, "#") {
+		return section
+	}
+	return "### " + section
+}
+
+// changelogRange returns the range of lines in which the entries of the
+// heading, or of the section within it when the section is set, are listed.
+// The range ends at the next release heading, or at the next heading after the
+// section. It is empty when the heading or section does not exist, so entries
+// of released versions are never matched.
+func changelogRange(lines []string, heading, section string) (int, int) {
+	h := indexOfLine(lines, heading, 0, len(lines))
+	if h == -1 {
+		return 0, 0
+	}
+	end := nextChangelogHeading(lines, h+1, "## ")
+	if section == "" {
+		return h + 1, end
+	}
+	at := indexOfLine(lines, section, h+1, end)
+	if at == -1 {
+		return 0, 0
+	}
+	return at + 1, nextChangelogHeading(lines, at+1, "#")
+}
+
+// hasChangelogLine returns true when the changelog contains the line below the
+// heading, or below the section within it when the section is set.
+func hasChangelogLine(content, heading, section, line string) bool {
+	lines := strings.Split(content, "\n")
+	start, end := changelogRange(lines, heading, section)
+	return indexOfLine(lines, line, start, end) != -1
+}
+
+// containsChangelogLine returns true when the changelog contains the line
+// below any heading.
+func containsChangelogLine(content, line string) bool {
+	lines := strings.Split(content, "\n")
+	return indexOfLine(lines, line, 0, len(lines)) != -1
+}
+
+// addChangelogLine inserts the line at the end of the list below the heading,
+// or below the section heading within it when the section is set. Missing
+// headings are added before the first release heading, and the changelog is
+// created when it does not exist. The content is returned as is when the
+// heading or section already contains the line.
+func addChangelogLine(content, heading, section, line string) string {
+	if hasChangelogLine(content, heading, section, line) {
+		return content
+	}
+	crlf := strings.Contains(content, "\r\n")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if strings.TrimSpace(content) == "" {
+		content = changelogTitle + "\n"
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	h := indexOfLine(lines, heading, 0, len(lines))
+	if h == -1 {
+		h = len(lines)
+		for i, l := range lines {
+			if strings.HasPrefix(l, "## ") {
+				h = i
+				break
+			}
+		}
+		lines = insertLines(lines, h, paragraph(lines, h, heading)...)
+		h = indexOfLine(lines, heading, 0, len(lines))
+	}
+	end := nextChangelogHeading(lines, h+1, "## ")
+	at := h
+	if section != "" {
+		at = indexOfLine(lines, section, h+1, end)
+		if at == -1 {
+			at = lastLine(lines, h, end) + 1
+			lines = insertLines(lines, at, paragraph(lines, at, section)...)
+			at = indexOfLine(lines, section, h+1, len(lines))
+		}
+	}
+	// The line is added to the list which directly follows the heading.
+	listEnd := nextChangelogHeading(lines, at+1, "#")
+	last := lastLine(lines, at, listEnd)
+	if last == at {
+		lines = insertLines(lines, at+1, paragraph(lines, at+1, line)...)
+	} else {
+		lines = insertLines(lines, last+1, line)
+	}
+
+	content = strings.Join(lines, "\n") + "\n"
+	if crlf {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	return content
+}
+
+// removeChangelogLine removes the line from below the heading, or from below
+// the section within it when the section is set, together with a blank line
+// which would follow another blank line. Lines of other headings, such as
+// released versions, are left as is.
+func removeChangelogLine(content, heading, section, line string) string {
+	lines := strings.SplitAfter(content, "\n")
+	start, end := changelogRange(lines, heading, section)
+	i := indexOfLine(lines, line, start, end)
+	if i == -1 {
+		return content
+	}
+	next := i + 1
+	if i > 0 && next < len(lines) && strings.TrimSpace(lines[i-1]) == "" && strings.TrimSpace(lines[next]) == "" {
+		next++
+	}
+	return strings.Join(append(lines[:i], lines[next:]...), "")
+}
+
+// indexOfLine returns the index of the line between start and end, or -1 when
+// it is not found.
+func indexOfLine(lines []string, line string, start, end int) int {
+	for i := start; i < end; i++ {
+		if strings.TrimSpace(lines[i]) == line {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextChangelogHeading returns the index of the next heading starting with the
+// prefix, or the number of lines when there is none.
+func nextChangelogHeading(lines []string, start int, prefix string) int {
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], prefix) {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// lastLine returns the index of the last line which is not blank between
+// start and end.
+func lastLine(lines []string, start, end int) int {
+	for i := end - 1; i > start; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return start
+}
+
+// paragraph returns the text surrounded by the blank lines which are needed to
+// separate it from the lines before and after the index.
+func paragraph(lines []string, at int, text string) []string {
+	p := []string{text}
+	if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+		p = append([]string{""}, p...)
+	}
+	if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		p = append(p, "")
+	}
+	return p
+}
+
+func insertLines(lines []string, at int, insert ...string) []string {
+	result := append([]string{}, lines[:at]...)
+	result = append(result, insert...)
+	return append(result, lines[at:]...)
+}
@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type ChangelogEntryResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Branch      types.String   `tfsdk:"branch"`
+	Path        types.String   `tfsdk:"path"`
+	Heading     types.String   `tfsdk:"heading"`
+	Section     types.String   `tfsdk:"section"`
+	Entry       types.String   `tfsdk:"entry"`
+	CommitSHA   types.String   `tfsdk:"commit_sha"`
+	AuthorName  types.String   `tfsdk:"author_name"`
+	AuthorEmail types.String   `tfsdk:"author_email"`
+	Message     types.String   `tfsdk:"message"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+var _ resource.Resource = &ChangelogEntryResource{}
+var _ resource.ResourceWithModifyPlan = &ChangelogEntryResource{}
+
+func NewChangelogEntryResource() resource.Resource {
+	return &ChangelogEntryResource{}
+}
+
+type ChangelogEntryResource struct {
+	prd *ProviderResourceData
+}
+
+func (r *ChangelogEntryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_changelog_entry"
+}
+
+func (r *ChangelogEntryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Changelog entry resource, which adds an entry to a changelog with the [keep a changelog](https://keepachangelog.com) layout. Only the entry is owned by the resource, the rest of the changelog is left as is.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch to write the entry to. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the changelog relative to the root of the repository, or to the provider path prefix. The changelog is created when it does not exist. Defaults to `CHANGELOG.md`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultChangelogPath),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"heading": schema.StringAttribute{
+				Description: "Heading or marker line which the entry is added below. The heading is added before the first release heading when it does not exist. Defaults to `## [Unreleased]`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultChangelogHeading),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.SingleLine(),
+				},
+			},
+			"section": schema.StringAttribute{
+				Description: "Section below the heading which the entry is added to, for example `Added` or `Fixed`. Names without a leading `#` are written as `### <name>`. The section is added when it does not exist.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.SingleLine(),
+				},
+			},
+			"entry": schema.StringAttribute{
+				Description: "Entry which is added to the list, and prefixed with `- ` unless it already is a list item. The entry is added again when it is removed from the changelog outside of Terraform, but it is not moved when the heading is renamed on a release.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.SingleLine(),
+				},
+			},
+			"commit_sha": schema.StringAttribute{
+				Description: "SHA of the commit which added the entry, or of the head commit of the branch when the changelog already contained the entry.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"author_name": schema.StringAttribute{
+				Description: "Author name of the commit. Defaults to the provider commits author name.",
+				Optional:    true,
+			},
+			"author_email": schema.StringAttribute{
+				Description: "Author email of the commit. Defaults to the provider commits author email.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"message": schema.StringAttribute{
+				Description: "Commit message. Defaults to the provider commits message.",
+				Optional:    true,
+			},
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+func (r *ChangelogEntryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+// ModifyPlan checks the branch against the protected branches, and the path
+// against the path policy.
+func (r *ChangelogEntryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.prd == nil {
+		return
+	}
+
+	var branch, p types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("branch"), &branch)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &p)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.prd.validateBranch(ctx, branch)...)
+	if !p.IsUnknown() {
+		resp.Diagnostics.Append(r.prd.validatePath(path.Root("path"), r.prd.RepositoryPath(p.ValueString()))...)
+	}
+}
+
+func (r *ChangelogEntryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ChangelogEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	branch, err := r.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", r.prd.redact(err.Error()))
+		return
+	}
+	data.Branch = types.StringValue(branch)
+
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	heading := data.Heading.ValueString()
+	section := changelogSection(data.Section.ValueString())
+	line := changelogLine(data.Entry.ValueString())
+	hash, err := r.prd.ApplyChange(ctx, createTimeout, branch, commit, repository.PushConfig{}, fileChange{
+		path: r.prd.RepositoryPath(data.Path.ValueString()),
+		edit: func(content string, exists bool) (string, error) {
+			return addChangelogLine(content, heading, section, line), nil
+		},
+	})
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Changelog Error", err)
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s:%s", branch, data.Path.ValueString(), line))
+	data.CommitSHA = types.StringValue(hash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read removes the resource from the state when the changelog does not contain
+// the entry, so that it is added again.
+func (r *ChangelogEntryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ChangelogEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	p := r.prd.RepositoryPath(data.Path.ValueString())
+	b, _, err := r.prd.ReadFile(ctx, data.Branch.ValueString(), p)
+	if errors.Is(err, os.ErrNotExist) {
+		tflog.Warn(ctx, "Removing resource from state as the changelog does not exist", map[string]interface{}{"path": p})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Changelog Read Error", err)
+		return
+	}
+	// The entry is looked for in the whole changelog, as it is moved below the
+	// heading of the version when it is released.
+	if !containsChangelogLine(string(b), changelogLine(data.Entry.ValueString())) {
+		tflog.Warn(ctx, "Removing resource from state as the changelog does not contain the entry", map[string]interface{}{"path": p})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+}
+
+// Update only changes the commit attributes, as changes to the entry replace
+// the resource.
+func (r *ChangelogEntryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ChangelogEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ChangelogEntryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ChangelogEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	heading := data.Heading.ValueString()
+	section := changelogSection(data.Section.ValueString())
+	line := changelogLine(data.Entry.ValueString())
+	_, err := r.prd.ApplyChange(ctx, deleteTimeout, data.Branch.ValueString(), commit, repository.PushConfig{}, fileChange{
+		path: r.prd.RepositoryPath(data.Path.ValueString()),
+		edit: func(content string, exists bool) (string, error) {
+			if !exists {
+				return "", os.ErrNotExist
+			}
+			return removeChangelogLine(content, heading, section, line), nil
+		},
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Changelog Error", err)
+		return
+	}
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/fluxcd/pkg/git"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// cloneOptions configures how much of the repository is fetched.
+type cloneOptions struct {
+	depth        int
+	singleBranch bool
+	tags         extgogit.TagMode
+	noCheckout   bool
+}
+
+func newCloneOptions(c *Clone) cloneOptions {
+	opts := cloneOptions{
+		singleBranch: true,
+		tags:         extgogit.NoTags,
+	}
+	if c == nil {
+		return opts
+	}
+	if !c.SingleBranch.IsNull() {
+		opts.singleBranch = c.SingleBranch.ValueBool()
+	}
+	if !c.NoTags.IsNull() && !c.NoTags.ValueBool() {
+		opts.tags = extgogit.TagFollowing
+	}
+	opts.noCheckout = c.NoCheckout.ValueBool()
+	if c.Shallow.ValueBool() {
+		opts.depth = 1
+	}
+	if !c.Depth.IsNull() {
+		opts.depth = int(c.Depth.ValueInt64())
+	}
+	return opts
+}
+
+// checkCredentialsOverHTTP returns an error when credentials would be sent
+// over plain HTTP without being explicitly allowed.
+func (prd *ProviderResourceData) checkCredentialsOverHTTP(u *url.URL, authOpts *git.AuthOptions) error {
+	hasCredentials := authOpts.Username != "" || authOpts.Password != "" || authOpts.BearerToken != ""
+	if u.Scheme == "http" && hasCredentials && (prd.http == nil || !prd.http.InsecureHttpAllowed.ValueBool()) {
+		return fmt.Errorf("credentials cannot be sent over HTTP")
+	}
+	return nil
+}
+
+// clone clones the branch into the empty directory. An empty repository is
+// initialized when the remote repository does not contain any commits. A bare
+// repository is cloned when the worktree should not be checked out.
+func (prd *ProviderResourceData) clone(ctx context.Context, dir, branch string, authOpts *git.AuthOptions, proxyOpts transport.ProxyOptions) (*extgogit.Repository, error) {
+	u, err := url.Parse(prd.url)
+	if err != nil {
+		return nil, err
+	}
+	err = prd.checkCredentialsOverHTTP(u, authOpts)
+	if err != nil {
+		return nil, err
+	}
+	authMethod, err := transportAuth(authOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	repo, err := extgogit.PlainCloneContext(ctx, dir, prd.cloneOptions.noCheckout, &extgogit.CloneOptions{
+		URL:           prd.url,
+		Auth:          authMethod,
+		RemoteName:    extgogit.DefaultRemoteName,
+		ReferenceName: branchRef,
+		SingleBranch:  prd.cloneOptions.singleBranch,
+		Depth:         prd.cloneOptions.depth,
+		Tags:          prd.cloneOptions.tags,
+		ProxyOptions:  proxyOpts,
+	})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return initRepository(dir, prd.url, branchRef, prd.cloneOptions.noCheckout)
+	}
+	if isBranchNotFound(err) && prd.baseBranch != "" && prd.baseBranch != branch {
+		tflog.Info(ctx, "Creating branch from the base branch as it does not exist", map[string]interface{}{"branch": branch, "base": prd.baseBranch})
+		return prd.cloneBaseBranch(ctx, dir, branch, authOpts, proxyOpts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone '%s': %w", u.Redacted(), err)
+	}
+	return repo, nil
+}
+
+// cloneBaseBranch clones the base branch and creates the branch from it, so
+// that the branch is created in the remote repository when pushing.
+func (prd *ProviderResourceData) cloneBaseBranch(ctx context.Context, dir, branch string, authOpts *git.AuthOptions, proxyOpts transport.ProxyOptions) (*extgogit.Repository, error) {
+	repo, err := prd.clone(ctx, dir, prd.baseBranch, authOpts, proxyOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone base branch %s: %w", prd.baseBranch, err)
+	}
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	err = repo.Storer.SetReference(plumbing.NewHashReference(branchRef, head.Hash()))
+	if err != nil {
+		return nil, err
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef))
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initRepository initializes an empty repository with HEAD pointing to the
+// branch, for remote repositories which do not contain any commits yet.
+func initRepository(dir, repoURL string, branchRef plumbing.ReferenceName, bare bool) (*extgogit.Repository, error) {
+	repo, err := extgogit.PlainInit(dir, bare)
+	if err != nil {
+		return nil, err
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: extgogit.DefaultRemoteName,
+		URLs: []string{repoURL},
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef))
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// acquireClone blocks until a clone or fetch is allowed to run. Clones are
+// limited separately from other operations, and the clone limit is acquired
+// first so that waiting clones do not block other operations.
+func (prd *ProviderResourceData) acquireClone(ctx context.Context) (func(), error) {
+	err := prd.clones.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = prd.operations.Acquire(ctx)
+	if err != nil {
+		prd.clones.Release()
+		return nil, err
+	}
+	return func() {
+		prd.operations.Release()
+		prd.clones.Release()
+	}, nil
+}
+
+// refresh updates the working copy of the client to the remote branch.
+func (prd *ProviderResourceData) refresh(ctx context.Context, client *GitClient, branch string) error {
+	err := client.credentialHelper.Fill(ctx, client.url, client.authOpts)
+	if err != nil {
+		return err
+	}
+	release, err := prd.acquireClone(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	fetchCtx, cancel := withPhaseTimeout(withHTTPTransport(ctx, prd.httpTransport), prd.phaseTimeouts.clone)
+	defer cancel()
+	err = prd.update(fetchCtx, client.repo, branch, client.authOpts, client.proxy)
+	return phaseError(ctx, fetchCtx, operationClone, prd.phaseTimeouts.clone, err)
+}
+
+// update fetches the branch into an existing working copy and resets the
+// worktree to it, discarding any local changes. Only the branch is updated in
+// bare repositories.
+func (prd *ProviderResourceData) update(ctx context.Context, repo *extgogit.Repository, branch string, authOpts *git.AuthOptions, proxyOpts transport.ProxyOptions) error {
+	authMethod, err := transportAuth(authOpts)
+	if err != nil {
+		return err
+	}
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	remoteRef := plumbing.NewRemoteReferenceName(extgogit.DefaultRemoteName, branch)
+	err = repo.FetchContext(ctx, &extgogit.FetchOptions{
+		RemoteName:   extgogit.DefaultRemoteName,
+		RefSpecs:     []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branchRef, remoteRef))},
+		Depth:        prd.cloneOptions.depth,
+		Auth:         authMethod,
+		Tags:         prd.cloneOptions.tags,
+		Force:        true,
+		ProxyOptions: proxyOpts,
+	})
+	if err != nil && !errors.Is(err, extgogit.NoErrAlreadyUpToDate) {
+		return err
+	}
+	remote, err := repo.Reference(remoteRef, true)
+	if err != nil {
+		return err
+	}
+	err = repo.Storer.SetReference(plumbing.NewHashReference(branchRef, remote.Hash()))
+	if err != nil {
+		return err
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef))
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if errors.Is(err, extgogit.ErrIsBareRepository) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = wt.Reset(&extgogit.ResetOptions{Commit: remote.Hash(), Mode: extgogit.HardReset})
+	if err != nil {
+		return err
+	}
+	return wt.Clean(&extgogit.CleanOptions{Dir: true})
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultAuthorName = "Terraform Provider Git"
+	defaultMessage    = "Write file with Terraform Provider Git."
+)
+
+// commitDefaults are used for commit attributes which are not set in the resource.
+type commitDefaults struct {
+	authorName  string
+	authorEmail string
+	message     string
+}
+
+func newCommitDefaults(c *Commits) commitDefaults {
+	defaults := commitDefaults{
+		authorName: defaultAuthorName,
+		message:    defaultMessage,
+	}
+	if c == nil {
+		return defaults
+	}
+	if c.AuthorName.ValueString() != "" {
+		defaults.authorName = c.AuthorName.ValueString()
+	}
+	if c.AuthorEmail.ValueString() != "" {
+		defaults.authorEmail = c.AuthorEmail.ValueString()
+	}
+	if c.Message.ValueString() != "" {
+		defaults.message = c.Message.ValueString()
+	}
+	return defaults
+}
+
+// commit returns the commit information, using the defaults for the values
+// which are not set.
+func (d commitDefaults) commit(message, authorName, authorEmail types.String) git.Commit {
+	commit := git.Commit{
+		Message: d.message,
+		Author: git.Signature{
+			Name:  d.authorName,
+			Email: d.authorEmail,
+		},
+	}
+	if message.ValueString() != "" {
+		commit.Message = message.ValueString()
+	}
+	if authorName.ValueString() != "" {
+		commit.Author.Name = authorName.ValueString()
+	}
+	if authorEmail.ValueString() != "" {
+		commit.Author.Email = authorEmail.ValueString()
+	}
+	return commit
+}
+
+var trailerRegex = regexp.MustCompile(`^[A-Za-z0-9-]+: `)
+
+// addTrailer appends a trailer to the commit message, adding it to the
+// existing trailer block when the last paragraph only contains trailers.
+func addTrailer(message, key, value string) string {
+	message = strings.TrimRight(message, "\n")
+	trailer := fmt.Sprintf("%s: %s", key, value)
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if len(paragraphs) > 1 && isTrailerBlock(last) {
+		return message + "\n" + trailer + "\n"
+	}
+	return message + "\n\n" + trailer + "\n"
+}
+
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerRegex.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// addChangeID appends a Gerrit Change-Id trailer to the commit message unless
+// it already contains one.
+func addChangeID(message string) (string, error) {
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "Change-Id: ") {
+			return message, nil
+		}
+	}
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	// Gerrit only requires the id to be unique, it does not have to be derived from the commit.
+	h := sha1.New()
+	h.Write([]byte(message))
+	h.Write(b)
+	return addTrailer(message, "Change-Id", fmt.Sprintf("I%x", h.Sum(nil))), nil
+}
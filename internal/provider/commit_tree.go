@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// emptyTreeHash is the hash of a tree without any entries.
+var emptyTreeHash = plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904")
+
+func isBare(repo *extgogit.Repository) bool {
+	_, err := repo.Worktree()
+	return errors.Is(err, extgogit.ErrIsBareRepository)
+}
+
+// headCommit returns the commit HEAD points to, or nil when the branch does
+// not contain any commits yet.
+func headCommit(repo *extgogit.Repository) (*object.Commit, error) {
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return repo.CommitObject(head.Hash())
+}
+
+// treeFileExists returns true when the file exists in the tree of HEAD.
+func treeFileExists(repo *extgogit.Repository, p string) (bool, error) {
+	commit, err := headCommit(repo)
+	if err != nil || commit == nil {
+		return false, err
+	}
+	_, err = commit.File(treePath(p))
+	if errors.Is(err, object.ErrFileNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func treePath(p string) string {
+	return strings.TrimPrefix(path.Clean(p), "/")
+}
+
+// commitTree creates a commit from the tree of HEAD with the files written and
+// the removed files left out, without using a worktree. HEAD is updated to the
+// new commit.
+func (c *GitClient) commitTree(info git.Commit, files map[string]io.Reader) (string, error) {
+	changes := map[string]*treeChange{}
+	for p := range c.removals {
+		changes[treePath(p)] = nil
+	}
+	c.removals = map[string]struct{}{}
+	modes := c.modes
+	c.modes = map[string]filemode.FileMode{}
+	for p, r := range files {
+		hash, err := writeBlob(c.repo.Storer, r)
+		if err != nil {
+			return "", err
+		}
+		changes[treePath(p)] = &treeChange{hash: hash, mode: modes[p]}
+	}
+
+	parent, err := headCommit(c.repo)
+	if err != nil {
+		return "", err
+	}
+	var tree *object.Tree
+	if parent != nil {
+		tree, err = parent.Tree()
+		if err != nil {
+			return "", err
+		}
+	}
+	treeHash, err := writeTree(c.repo.Storer, tree, changes)
+	if err != nil {
+		return "", err
+	}
+	if parent != nil && treeHash == parent.TreeHash {
+		return parent.Hash.String(), git.ErrNoStagedFiles
+	}
+
+	signature := object.Signature{
+		Name:  info.Author.Name,
+		Email: info.Author.Email,
+		When:  time.Now(),
+	}
+	commit := &object.Commit{
+		Author:    signature,
+		Committer: signature,
+		Message:   info.Message,
+		TreeHash:  treeHash,
+	}
+	if parent != nil {
+		commit.ParentHashes = []plumbing.Hash{parent.Hash}
+	}
+	if c.signer != nil {
+		commit.PGPSignature, err = c.signer.sign(commit)
+		if err != nil {
+			return "", err
+		}
+	}
+	obj := c.repo.Storer.NewEncodedObject()
+	err = commit.Encode(obj)
+	if err != nil {
+		return "", err
+	}
+	hash, err := c.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := c.repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", err
+	}
+	name := head.Name()
+	if head.Type() == plumbing.SymbolicReference {
+		name = head.Target()
+	}
+	err = c.repo.Storer.SetReference(plumbing.NewHashReference(name, hash))
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+func writeBlob(s storer.EncodedObjectStorer, r io.Reader) (plumbing.Hash, error) {
+	obj := s.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	_, err = io.Copy(w, r)
+	if err != nil {
+		w.Close()
+		return plumbing.ZeroHash, err
+	}
+	err = w.Close()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
+
+// treeChange is a file written to a tree. The mode of an existing file is kept
+// when the mode is empty.
+type treeChange struct {
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+// writeTree writes a copy of the tree with the changes applied and returns its
+// hash. Changes map file paths to the new file, or to nil when the file is
+// removed. Directories left without entries are removed.
+func writeTree(s storer.EncodedObjectStorer, tree *object.Tree, changes map[string]*treeChange) (plumbing.Hash, error) {
+	entries := map[string]object.TreeEntry{}
+	if tree != nil {
+		for _, entry := range tree.Entries {
+			entries[entry.Name] = entry
+		}
+	}
+
+	dirChanges := map[string]map[string]*treeChange{}
+	for p, change := range changes {
+		dir, rest, ok := strings.Cut(p, "/")
+		if ok {
+			if dirChanges[dir] == nil {
+				dirChanges[dir] = map[string]*treeChange{}
+			}
+			dirChanges[dir][rest] = change
+			continue
+		}
+		if change == nil {
+			delete(entries, p)
+			continue
+		}
+		mode := change.mode
+		if mode == filemode.Empty {
+			mode = filemode.Regular
+			if entry, ok := entries[p]; ok && entry.Mode == filemode.Executable {
+				mode = filemode.Executable
+			}
+		}
+		entries[p] = object.TreeEntry{Name: p, Mode: mode, Hash: change.hash}
+	}
+	for dir, changes := range dirChanges {
+		var subtree *object.Tree
+		if entry, ok := entries[dir]; ok && entry.Mode == filemode.Dir {
+			var err error
+			subtree, err = object.GetTree(s, entry.Hash)
+			if err != nil {
+				return plumbing.ZeroHash, fmt.Errorf("could not read tree %s: %w", dir, err)
+			}
+		}
+		hash, err := writeTree(s, subtree, changes)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if hash == emptyTreeHash {
+			delete(entries, dir)
+			continue
+		}
+		entries[dir] = object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: hash}
+	}
+
+	newTree := &object.Tree{}
+	for _, entry := range entries {
+		newTree.Entries = append(newTree.Entries, entry)
+	}
+	// Git sorts tree entries as if directory names end with a slash.
+	sortName := func(entry object.TreeEntry) string {
+		if entry.Mode == filemode.Dir {
+			return entry.Name + "/"
+		}
+		return entry.Name
+	}
+	sort.Slice(newTree.Entries, func(i, j int) bool {
+		return sortName(newTree.Entries[i]) < sortName(newTree.Entries[j])
+	})
+	obj := s.NewEncodedObject()
+	err := newTree.Encode(obj)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
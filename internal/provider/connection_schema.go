@@ -0,0 +1,163 @@
+package provider
+
+import (
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type connectionAttributeKind int
+
+const (
+	connectionString connectionAttributeKind = iota
+	connectionBool
+	connectionList
+	connectionMap
+)
+
+// connectionAttribute is an optional attribute of the ssh and http blocks,
+// which are configured both in the provider and in the repository block of
+// resources. The provider and resource schemas use different types, so both
+// are built from the same attributes.
+type connectionAttribute struct {
+	name        string
+	kind        connectionAttributeKind
+	description string
+	sensitive   bool
+	validators  []validator.String
+}
+
+func sshAttributes() []connectionAttribute {
+	return []connectionAttribute{
+		{
+			name:        "username",
+			description: "Username for Git SSH server.",
+		},
+		{
+			name:        "password",
+			description: "Password for private key.",
+			sensitive:   true,
+		},
+		{
+			name:        "private_key",
+			description: "Private key used for authenticating to the Git SSH server.",
+			sensitive:   true,
+		},
+		{
+			name:        "proxy_url",
+			description: "SOCKS5 proxy used to reach the Git SSH server, for example `socks5://bastion:1080`.",
+			validators: []validator.String{
+				validators.URLScheme("socks5", "socks5h"),
+			},
+		},
+		{
+			name:        "known_hosts",
+			description: "Host keys of the Git SSH server in the known hosts format. The host key is scanned once per provider process and trusted on first use when not set.",
+		},
+		{
+			name:        "offline_known_hosts",
+			kind:        connectionBool,
+			description: "Forbids scanning the host key of the Git SSH server, which requires `known_hosts` to be set.",
+		},
+	}
+}
+
+func httpAttributes() []connectionAttribute {
+	return []connectionAttribute{
+		{
+			name:        "username",
+			description: "Username for basic authentication.",
+		},
+		{
+			name:        "password",
+			description: "Password for basic authentication.",
+			sensitive:   true,
+		},
+		{
+			name:        "allow_insecure_http",
+			kind:        connectionBool,
+			description: "Allows http Git url connections.",
+		},
+		{
+			name:        "certificate_authority",
+			description: "Certificate authority to validate self-signed certificates. The certificates are trusted in addition to the system trust store.",
+		},
+		{
+			name:        "certificate_authority_file",
+			description: "Path of a PEM encoded certificate authority bundle, for example a corporate intermediate CA. The certificates are trusted in addition to the system trust store and `certificate_authority`.",
+		},
+		{
+			name:        "client_certificate",
+			description: "PEM encoded client certificate used for mutual TLS authentication.",
+		},
+		{
+			name:        "client_key",
+			description: "PEM encoded private key of the client certificate.",
+			sensitive:   true,
+		},
+		{
+			name:        "insecure_skip_tls_verify",
+			kind:        connectionBool,
+			description: "Skips verification of the Git server certificate. This should only be used for testing.",
+		},
+		{
+			name:        "tls_min_version",
+			description: "Minimum TLS version of HTTPS connections, out of `1.0`, `1.1`, `1.2` and `1.3`. Defaults to `1.2`.",
+			validators: []validator.String{
+				validators.OneOf(tlsVersionNames()...),
+			},
+		},
+		{
+			name:        "tls_cipher_suites",
+			kind:        connectionList,
+			description: "Cipher suites allowed for TLS 1.2 and earlier connections, for example `TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384`. Only the secure cipher suites supported by Go can be used, and the cipher suites of TLS 1.3 are not configurable.",
+		},
+		{
+			name:        "user_agent",
+			description: "User-Agent sent with git HTTP requests instead of the go-git default.",
+		},
+		{
+			name:        "headers",
+			kind:        connectionMap,
+			description: "Additional headers sent with all git HTTP requests, for example a request id or an authentication header required by a proxy. The values of the `Authorization` and `Proxy-Authorization` headers and of headers ending with `-Token` are masked in logs and errors.",
+			sensitive:   true,
+		},
+	}
+}
+
+func providerConnectionAttribute(attrs []connectionAttribute) pschema.SingleNestedAttribute {
+	attributes := map[string]pschema.Attribute{}
+	for _, a := range attrs {
+		switch a.kind {
+		case connectionString:
+			attributes[a.name] = pschema.StringAttribute{Description: a.description, Optional: true, Sensitive: a.sensitive, Validators: a.validators}
+		case connectionBool:
+			attributes[a.name] = pschema.BoolAttribute{Description: a.description, Optional: true, Sensitive: a.sensitive}
+		case connectionList:
+			attributes[a.name] = pschema.ListAttribute{Description: a.description, ElementType: types.StringType, Optional: true, Sensitive: a.sensitive}
+		case connectionMap:
+			attributes[a.name] = pschema.MapAttribute{Description: a.description, ElementType: types.StringType, Optional: true, Sensitive: a.sensitive}
+		}
+	}
+	return pschema.SingleNestedAttribute{Attributes: attributes, Optional: true}
+}
+
+func resourceConnectionAttribute(attrs []connectionAttribute) rschema.SingleNestedAttribute {
+	attributes := map[string]rschema.Attribute{}
+	for _, a := range attrs {
+		switch a.kind {
+		case connectionString:
+			attributes[a.name] = rschema.StringAttribute{Description: a.description, Optional: true, Sensitive: a.sensitive, Validators: a.validators}
+		case connectionBool:
+			attributes[a.name] = rschema.BoolAttribute{Description: a.description, Optional: true, Sensitive: a.sensitive}
+		case connectionList:
+			attributes[a.name] = rschema.ListAttribute{Description: a.description, ElementType: types.StringType, Optional: true, Sensitive: a.sensitive}
+		case connectionMap:
+			attributes[a.name] = rschema.MapAttribute{Description: a.description, ElementType: types.StringType, Optional: true, Sensitive: a.sensitive}
+		}
+	}
+	return rschema.SingleNestedAttribute{Attributes: attributes, Optional: true}
+}
@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// credentialExpiryMargin is subtracted from the credential expiry so that
+// credentials do not expire while an operation is in progress.
+const credentialExpiryMargin = 30 * time.Second
+
+type credentials struct {
+	username    string
+	password    string
+	bearerToken string
+	expiry      time.Time
+}
+
+func (c credentials) expired() bool {
+	return time.Now().Add(credentialExpiryMargin).After(c.expiry)
+}
+
+// credentialHelper obtains credentials from an external command using the
+// git-credential protocol. Credentials are cached until they expire, either
+// when the helper declares an expiry or when a ttl is configured.
+type credentialHelper struct {
+	command string
+	args    []string
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]credentials
+	// secrets are all passwords and tokens returned by the helper, which are
+	// redacted from logs and diagnostics.
+	secrets map[string]bool
+}
+
+func newCredentialHelper(ctx context.Context, ch *CredentialHelper) (*credentialHelper, diag.Diagnostics) {
+	if ch == nil {
+		return nil, nil
+	}
+	args := []string{}
+	if !ch.Args.IsNull() && !ch.Args.IsUnknown() {
+		diags := ch.Args.ElementsAs(ctx, &args, false)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+	var ttl time.Duration
+	if !ch.Ttl.IsNull() {
+		var err error
+		ttl, err = time.ParseDuration(ch.Ttl.ValueString())
+		if err != nil {
+			diags := diag.Diagnostics{}
+			diags.AddAttributeError(path.Root("credential_helper").AtName("ttl"), "Invalid Duration", err.Error())
+			return nil, diags
+		}
+	}
+	return &credentialHelper{
+		command: ch.Command.ValueString(),
+		args:    args,
+		ttl:     ttl,
+		cache:   map[string]credentials{},
+		secrets: map[string]bool{},
+	}, nil
+}
+
+// Fill sets the credentials for the url in the auth options, executing the
+// credential helper unless valid credentials are cached. It is a no-op when no
+// credential helper is configured.
+func (ch *credentialHelper) Fill(ctx context.Context, u *url.URL, authOpts *git.AuthOptions) error {
+	if ch == nil {
+		return nil
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	key := fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path)
+	creds, ok := ch.cache[key]
+	if !ok || creds.expired() {
+		var err error
+		creds, err = ch.get(ctx, u, authOpts.Username)
+		if err != nil {
+			return err
+		}
+		if !creds.expiry.IsZero() {
+			ch.cache[key] = creds
+		}
+		for _, secret := range []string{creds.password, creds.bearerToken} {
+			if secret != "" {
+				ch.secrets[secret] = true
+			}
+		}
+	}
+
+	if creds.bearerToken != "" {
+		authOpts.Username = ""
+		authOpts.Password = ""
+		authOpts.BearerToken = creds.bearerToken
+		return nil
+	}
+	if creds.username != "" {
+		authOpts.Username = creds.username
+	}
+	if creds.password != "" {
+		authOpts.Password = creds.password
+	}
+	return nil
+}
+
+// Secrets returns the passwords and tokens which have been returned by the
+// credential helper.
+func (ch *credentialHelper) Secrets() []string {
+	if ch == nil {
+		return nil
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	secrets := []string{}
+	for secret := range ch.secrets {
+		secrets = append(secrets, secret)
+	}
+	return secrets
+}
+
+func (ch *credentialHelper) get(ctx context.Context, u *url.URL, username string) (credentials, error) {
+	input := &bytes.Buffer{}
+	fmt.Fprintf(input, "protocol=%s\n", u.Scheme)
+	fmt.Fprintf(input, "host=%s\n", u.Host)
+	fmt.Fprintf(input, "path=%s\n", strings.TrimPrefix(u.Path, "/"))
+	if username != "" {
+		fmt.Fprintf(input, "username=%s\n", username)
+	}
+	fmt.Fprint(input, "\n")
+
+	cmd := exec.CommandContext(ctx, ch.command, ch.args...)
+	cmd.Stdin = input
+	out, err := cmd.Output()
+	if err != nil {
+		return credentials{}, fmt.Errorf("credential helper %q failed: %w", ch.command, err)
+	}
+
+	attrs := parseCredentialAttributes(out)
+	creds := credentials{
+		username: attrs["username"],
+		password: attrs["password"],
+	}
+	if attrs["authtype"] == "Bearer" && attrs["credential"] != "" {
+		creds = credentials{
+			bearerToken: attrs["credential"],
+		}
+	}
+	if v, ok := attrs["password_expiry_utc"]; ok {
+		sec, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return credentials{}, fmt.Errorf("credential helper returned invalid password_expiry_utc: %w", err)
+		}
+		creds.expiry = time.Unix(sec, 0)
+	} else if ch.ttl > 0 {
+		creds.expiry = time.Now().Add(ch.ttl)
+	}
+	return creds, nil
+}
+
+// parseCredentialAttributes parses the key value pairs written by a credential
+// helper, stopping at the first blank line.
+func parseCredentialAttributes(b []byte) map[string]string {
+	attrs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		attrs[k] = v
+	}
+	return attrs
+}
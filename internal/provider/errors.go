@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// errorClass is the kind of a git error, which determines the diagnostic that
+// is reported and whether the operation is retried.
+type errorClass string
+
+const (
+	errorClassAuthentication errorClass = "authentication"
+	errorClassNotFound       errorClass = "not_found"
+	errorClassNetwork        errorClass = "network"
+	errorClassNonFastForward errorClass = "non_fast_forward"
+	errorClassRateLimit      errorClass = "rate_limit"
+	errorClassUnknown        errorClass = "unknown"
+)
+
+var errorClasses = []errorClass{
+	errorClassAuthentication,
+	errorClassNotFound,
+	errorClassNetwork,
+	errorClassNonFastForward,
+	errorClassRateLimit,
+	errorClassUnknown,
+}
+
+// defaultRetryableErrors are the error classes which are retried when the
+// provider does not configure retryable errors. Authentication and not found
+// errors are not retried as they do not resolve themselves.
+var defaultRetryableErrors = map[errorClass]bool{
+	errorClassNetwork:        true,
+	errorClassNonFastForward: true,
+	errorClassRateLimit:      true,
+}
+
+// classifyError returns the class of the git error.
+func classifyError(err error) errorClass {
+	status := httpStatusCode(err)
+	switch {
+	case errors.Is(err, transport.ErrAuthenticationRequired),
+		errors.Is(err, transport.ErrAuthorizationFailed),
+		strings.Contains(err.Error(), "unable to authenticate"):
+		return errorClassAuthentication
+	case errors.Is(err, transport.ErrRepositoryNotFound), isBranchNotFound(err):
+		return errorClassNotFound
+	case isNonFastForward(err):
+		return errorClassNonFastForward
+	case status == http.StatusTooManyRequests, strings.Contains(strings.ToLower(err.Error()), "rate limit"):
+		return errorClassRateLimit
+	case status >= http.StatusInternalServerError, isNetworkError(err):
+		return errorClassNetwork
+	default:
+		return errorClassUnknown
+	}
+}
+
+// isBranchNotFound returns true when cloning failed as the branch does not
+// exist in the remote repository.
+func isBranchNotFound(err error) bool {
+	var noMatchErr extgogit.NoMatchingRefSpecError
+	return errors.As(err, &noMatchErr) || errors.Is(err, plumbing.ErrReferenceNotFound)
+}
+
+// httpStatusCode returns the status code of an unexpected HTTP response, or
+// zero for other errors.
+func httpStatusCode(err error) int {
+	var unexpectedErr *plumbing.UnexpectedError
+	if !errors.As(err, &unexpectedErr) {
+		return 0
+	}
+	var httpErr *githttp.Err
+	if !errors.As(unexpectedErr.Err, &httpErr) {
+		return 0
+	}
+	return httpErr.StatusCode()
+}
+
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// newRetryableErrors returns the configured retryable error classes, or nil
+// when the default classes should be retried.
+func newRetryableErrors(ctx context.Context, classes types.List) (map[errorClass]bool, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if classes.IsNull() || classes.IsUnknown() {
+		return nil, diags
+	}
+	values := []string{}
+	diags.Append(classes.ElementsAs(ctx, &values, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	retryable := map[errorClass]bool{}
+OUTER:
+	for _, v := range values {
+		for _, class := range errorClasses {
+			if errorClass(v) == class {
+				retryable[class] = true
+				continue OUTER
+			}
+		}
+		diags.AddAttributeError(path.Root("retryable_errors"), "Invalid Error Class", fmt.Sprintf("Error class %q must be one of %v", v, errorClasses))
+	}
+	return retryable, diags
+}
+
+// isRetryable returns true when the class of the error is configured to be
+// retried.
+func (prd *ProviderResourceData) isRetryable(err error) bool {
+	if errors.Is(err, ErrReadOnly) {
+		return false
+	}
+	retryable := prd.retryableErrors
+	if retryable == nil {
+		retryable = defaultRetryableErrors
+	}
+	return retryable[classifyError(err)]
+}
+
+// errorClassDiagnostics are the summaries and hints of the diagnostics reported
+// for the error classes.
+var errorClassDiagnostics = map[errorClass]struct{ summary, hint string }{
+	errorClassAuthentication: {"Git Authentication Error", "Check the credentials configured for the repository and that they grant access to it."},
+	errorClassNotFound:       {"Git Not Found Error", "Check that the repository and branch exist and that the credentials grant access to them."},
+	errorClassNetwork:        {"Git Network Error", "The git server could not be reached. Network errors are retried until the timeout unless retryable_errors is configured without network."},
+	errorClassNonFastForward: {"Git Push Rejected", "The branch was updated while the change was pushed. Rejected pushes are retried until the timeout unless retryable_errors is configured without non_fast_forward."},
+	errorClassRateLimit:      {"Git Rate Limit Error", "The git server limited the number of requests. Rate limited requests are retried until the timeout unless retryable_errors is configured without rate_limit."},
+}
+
+// addGitError adds an error diagnostic for the git error, with a summary and
+// hint describing the class of the error when it is known. Credentials are
+// redacted from the error message.
+func (prd *ProviderResourceData) addGitError(diags *diag.Diagnostics, summary string, err error) {
+	detail := prd.redact(err.Error())
+	d, ok := errorClassDiagnostics[classifyError(err)]
+	if !ok {
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(d.summary, detail+"\n\n"+d.hint)
+}
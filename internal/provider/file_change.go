@@ -0,0 +1,361 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// fileChange is a change to a single file in the repository.
+type fileChange struct {
+	path string
+	// content opens the content of the file, and is nil when the file is removed.
+	// The content may be opened more than once when the change is retried.
+	content func() (io.ReadCloser, error)
+	// edit returns the content of the file from its current content, and is
+	// used instead of content for changes which only own a part of the file.
+	// The edit is applied again when the change is retried.
+	edit func(content string, exists bool) (string, error)
+	// mode is the mode of the written file. The mode of existing files is kept
+	// when not set.
+	mode filemode.FileMode
+	// create fails the change when the file already exists, unless override is set.
+	create   bool
+	override bool
+}
+
+// stringContent returns file content read from the string.
+func stringContent(s string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+// localFileContent returns file content read from a local file, so that the
+// content is not kept as a string. go-git still buffers the blob in memory
+// when it is written.
+func localFileContent(name string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+}
+
+// ApplyChange commits and pushes the file change to the branch. The change is
+// combined with the changes of concurrent operations when batch commits are
+// enabled. The hash of the commit containing the change is returned.
+func (prd *ProviderResourceData) ApplyChange(ctx context.Context, timeout time.Duration, branch string, commit git.Commit, pushConfig repository.PushConfig, change fileChange) (string, error) {
+	err := prd.checkBranch(branch)
+	if err != nil {
+		return "", err
+	}
+	err = prd.pathPolicy.check(change.path)
+	if err != nil {
+		return "", err
+	}
+	if prd.batcher != nil {
+		return prd.batcher.Submit(ctx, prd, branch, commit, pushConfig, change)
+	}
+	hash, errs := prd.commitChanges(ctx, timeout, branch, commit, pushConfig, []fileChange{change})
+	return hash, errs[0]
+}
+
+// maxRebaseAttempts is how many times the changes are applied on top of a
+// new remote head when the push is rejected, before cloning again.
+const maxRebaseAttempts = 5
+
+// commitChanges commits and pushes the file changes. When the push is rejected
+// as the branch has moved, the changes are applied again on top of the new
+// remote head. Other failures are retried with a new clone when the class of
+// the error is retryable. An error is
+// returned for each of the changes, as changes which cannot be applied are left
+// out of the commit.
+func (prd *ProviderResourceData) commitChanges(ctx context.Context, timeout time.Duration, branch string, commit git.Commit, pushConfig repository.PushConfig, changes []fileChange) (string, []error) {
+	errs := make([]error, len(changes))
+	for _, change := range changes {
+		err := prd.checkBranch(branch)
+		if err == nil {
+			err = prd.pathPolicy.check(change.path)
+		}
+		if err != nil {
+			for i := range errs {
+				errs[i] = err
+			}
+			return "", errs
+		}
+	}
+	hash := ""
+	err := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		client, err := prd.GetGitClient(ctx, branch)
+		if err != nil {
+			return prd.retryError(err)
+		}
+		defer client.Close()
+		for attempt := 1; ; attempt++ {
+			err = prd.verifier.verifyHead(client.repo)
+			if err != nil {
+				return retry.NonRetryableError(err)
+			}
+			_, op := prd.startOperation(ctx, operationCommit, branch)
+			hash, err = writeChanges(ctx, client, commit, changes, errs)
+			// The files may already have the expected content, in which case the
+			// head commit contains the changes.
+			if errors.Is(err, git.ErrNoStagedFiles) {
+				head, err := headCommit(client.repo)
+				hash = ""
+				if head != nil {
+					hash = head.Hash.String()
+				}
+				prd.endOperation(ctx, op, nil, hash, err)
+				if err != nil {
+					return retry.NonRetryableError(err)
+				}
+				return nil
+			}
+			prd.endOperation(ctx, op, changedPaths(changes, errs), hash, err)
+			if err != nil {
+				return retry.NonRetryableError(err)
+			}
+			pushCtx, op := prd.startOperation(ctx, operationPush, branch)
+			pushCtx, cancel := withPhaseTimeout(pushCtx, prd.phaseTimeouts.push)
+			err = client.Push(pushCtx, pushConfig)
+			err = phaseError(ctx, pushCtx, operationPush, prd.phaseTimeouts.push, err)
+			cancel()
+			prd.endOperation(ctx, op, changedPaths(changes, errs), hash, err)
+			prd.refsCache.Invalidate(prd.url)
+			if len(pushConfig.Refspecs) == 0 {
+				err = prd.pushRejected(ctx, client, branch, err)
+			}
+			if isNonFastForward(err) && attempt < maxRebaseAttempts {
+				prd.telemetry.retry(ctx, redactURLCredentials(prd.url), string(errorClassNonFastForward))
+				// Fetching into the existing clone does not work with Azure DevOps,
+				// so the repository is cloned again instead.
+				if prd.azureDevOps {
+					tflog.Debug(ctx, "Cloning the repository again as the push was rejected", map[string]interface{}{"branch": branch, "attempt": attempt})
+					return retry.RetryableError(err)
+				}
+				tflog.Debug(ctx, "Applying changes on top of the new remote head as the push was rejected", map[string]interface{}{"branch": branch, "attempt": attempt})
+				err = prd.refresh(ctx, client, branch)
+				// Fetching into the existing clone is not supported by all servers,
+				// so the repository is cloned again when it fails. Errors which
+				// are not retryable are returned when cloning.
+				if err != nil {
+					tflog.Debug(ctx, "Cloning the repository again as the working copy could not be updated", map[string]interface{}{"branch": branch, "error": prd.redact(err.Error())})
+					return retry.RetryableError(err)
+				}
+				continue
+			}
+			if err != nil {
+				if prd.isRetryable(err) {
+					prd.telemetry.retry(ctx, redactURLCredentials(prd.url), string(classifyError(err)))
+				}
+				return prd.retryError(err)
+			}
+			return nil
+		}
+	})
+	for i := range errs {
+		if errs[i] == nil {
+			errs[i] = err
+		}
+	}
+	return hash, errs
+}
+
+// changedPaths returns the paths of the changes which are applied.
+func changedPaths(changes []fileChange, errs []error) []string {
+	paths := []string{}
+	for i, change := range changes {
+		if errs[i] == nil {
+			paths = append(paths, change.path)
+		}
+	}
+	return paths
+}
+
+// retryError returns a retry error which is only retried when the class of the
+// error is retryable.
+func (prd *ProviderResourceData) retryError(err error) *retry.RetryError {
+	if prd.isRetryable(err) {
+		return retry.RetryableError(err)
+	}
+	return retry.NonRetryableError(err)
+}
+
+// writeChanges writes the file changes to the worktree and commits them. The
+// errors of changes which cannot be applied are set in errs.
+func writeChanges(ctx context.Context, client *GitClient, commit git.Commit, changes []fileChange, errs []error) (string, error) {
+	files := map[string]io.Reader{}
+	edited := map[string]string{}
+	closers := []io.Closer{}
+	defer func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}()
+	for i, change := range changes {
+		errs[i] = nil
+		exists, err := client.FileExists(change.path)
+		if err != nil {
+			return "", err
+		}
+		if change.edit != nil {
+			content, err := editFile(client, change, edited)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			edited[change.path] = content
+			files[change.path] = strings.NewReader(content)
+			continue
+		}
+		if change.content == nil {
+			if !exists {
+				tflog.Debug(ctx, "Skipping file removal as the file does not exist", map[string]interface{}{"path": change.path})
+				continue
+			}
+			err := client.Remove(change.path)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		if change.create && exists && !change.override {
+			// Existing files with the same content are adopted, so that resources
+			// can be created for files which were not written by Terraform.
+			identical, err := identicalFile(client, change)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			if !identical {
+				errs[i] = fmt.Errorf("cannot override existing file")
+				continue
+			}
+			tflog.Debug(ctx, "Adopting existing file as it has the same content", map[string]interface{}{"path": change.path})
+			continue
+		}
+		r, err := change.content()
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		closers = append(closers, r)
+		files[change.path] = r
+		if change.mode != filemode.Empty {
+			client.SetMode(change.path, change.mode)
+		}
+	}
+	return client.Commit(commit, repository.WithFiles(files))
+}
+
+// editFile returns the content of the file after applying the edit of the
+// change, to the content written by earlier changes of the same commit or to
+// the content in the tree of HEAD.
+func editFile(client *GitClient, change fileChange, edited map[string]string) (string, error) {
+	if content, ok := edited[change.path]; ok {
+		return change.edit(content, true)
+	}
+	commit, err := headCommit(client.repo)
+	if err != nil {
+		return "", err
+	}
+	if commit == nil {
+		return change.edit("", false)
+	}
+	file, err := commit.File(treePath(change.path))
+	if errors.Is(err, object.ErrFileNotFound) {
+		return change.edit("", false)
+	}
+	if err != nil {
+		return "", err
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return "", err
+	}
+	return change.edit(content, true)
+}
+
+// identicalFile returns true when the file in the tree of HEAD has the content
+// and mode of the change.
+func identicalFile(client *GitClient, change fileChange) (bool, error) {
+	commit, err := headCommit(client.repo)
+	if err != nil || commit == nil {
+		return false, err
+	}
+	file, err := commit.File(treePath(change.path))
+	if err != nil {
+		return false, err
+	}
+	if change.mode != filemode.Empty && file.Mode != change.mode {
+		return false, nil
+	}
+	existing, err := file.Reader()
+	if err != nil {
+		return false, err
+	}
+	defer existing.Close()
+	content, err := change.content()
+	if err != nil {
+		return false, err
+	}
+	defer content.Close()
+	existingSum, err := readerSHA256(existing)
+	if err != nil {
+		return false, err
+	}
+	contentSum, err := readerSHA256(content)
+	if err != nil {
+		return false, err
+	}
+	return existingSum == contentSum, nil
+}
+
+// errNonFastForward is wrapped around push errors when the remote branch has
+// moved since the commit was made.
+var errNonFastForward = errors.New("remote branch has moved")
+
+// pushRejected returns the push error wrapped with errNonFastForward when the
+// remote branch no longer points at the parent of the pushed commit. go-git
+// checks whether the push is a fast-forward before sending it, which fails
+// with an object not found error instead in shallow clones, as the remote head
+// is not in the local history. Errors are returned as is when the branch does
+// not exist or already points at the commit.
+func (prd *ProviderResourceData) pushRejected(ctx context.Context, client *GitClient, branch string, err error) error {
+	if err == nil || isNonFastForward(err) {
+		return err
+	}
+	remote, headErr := prd.RemoteHead(ctx, branch)
+	if headErr != nil || remote == "" {
+		return err
+	}
+	commit, headErr := headCommit(client.repo)
+	if headErr != nil || commit == nil || remote == commit.Hash.String() {
+		return err
+	}
+	if len(commit.ParentHashes) > 0 && remote == commit.ParentHashes[0].String() {
+		return err
+	}
+	return fmt.Errorf("%w: %s", errNonFastForward, err)
+}
+
+// isNonFastForward returns true when the push was rejected as the remote
+// branch contains commits which are not in the local branch, either by go-git
+// or by the server.
+func isNonFastForward(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, errNonFastForward) || strings.Contains(err.Error(), "non-fast-forward")
+}
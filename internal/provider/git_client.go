@@ -0,0 +1,320 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/fluxcd/pkg/ssh/knownhosts"
+	"github.com/go-git/go-billy/v5"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	readOnlyError = "error"
+	readOnlySkip  = "skip"
+)
+
+// ErrReadOnly is returned when pushing with a provider configured as read only.
+var ErrReadOnly = errors.New("provider is configured as read only, refusing to push")
+
+// GitClient is a working copy of the repository, which exposes remote options
+// that are not supported by the Flux client.
+type GitClient struct {
+	path             string
+	removePath       bool
+	bare             bool
+	removals         map[string]struct{}
+	modes            map[string]filemode.FileMode
+	repo             *extgogit.Repository
+	release          func()
+	url              *url.URL
+	authOpts         *git.AuthOptions
+	credentialHelper *credentialHelper
+	proxy            transport.ProxyOptions
+	httpTransport    http.RoundTripper
+	operations       semaphore
+	readOnly         string
+	gerrit           bool
+	signer           *signer
+	pushOptions      []string
+}
+
+// Path returns the path of the working copy.
+func (c *GitClient) Path() string {
+	return c.path
+}
+
+// Close releases the working copy so that it can be used by other operations.
+// Temporary clones are removed.
+func (c *GitClient) Close() {
+	if c.removePath {
+		os.RemoveAll(c.path)
+	}
+	c.release()
+}
+
+// FileExists returns true when the file exists in the working copy.
+func (c *GitClient) FileExists(p string) (bool, error) {
+	if c.bare {
+		return treeFileExists(c.repo, p)
+	}
+	_, err := os.Stat(filepath.Join(c.path, p))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Remove removes the file from the working copy, together with the parent
+// directories which are left empty. The file is removed from the tree of the
+// next commit in bare repositories, where empty directories are never written.
+func (c *GitClient) Remove(p string) error {
+	if c.bare {
+		c.removals[p] = struct{}{}
+		return nil
+	}
+	err := os.Remove(filepath.Join(c.path, p))
+	if err != nil {
+		return err
+	}
+	for dir := filepath.Dir(filepath.Clean(p)); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(filepath.Join(c.path, dir))
+		if err != nil || len(entries) > 0 {
+			return err
+		}
+		err = os.Remove(filepath.Join(c.path, dir))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetMode sets the mode of a file written by the next commit.
+func (c *GitClient) SetMode(p string, mode filemode.FileMode) {
+	c.modes[p] = mode
+}
+
+// Commit writes the files and commits all changes in the worktree, or creates
+// the commit from the tree of HEAD in bare repositories. A Change-Id trailer is
+// added to the message when pushing to Gerrit, and the commit is signed when a
+// signing key is configured.
+func (c *GitClient) Commit(info git.Commit, commitOpts ...repository.CommitOption) (string, error) {
+	options := &repository.CommitOptions{}
+	for _, o := range commitOpts {
+		o(options)
+	}
+	if c.gerrit {
+		message, err := addChangeID(info.Message)
+		if err != nil {
+			return "", err
+		}
+		info.Message = message
+	}
+	if c.bare {
+		return c.commitTree(info, options.Files)
+	}
+
+	wt, err := c.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	modes := c.modes
+	c.modes = map[string]filemode.FileMode{}
+	for path, content := range options.Files {
+		err := writeFile(wt.Filesystem, path, content)
+		if err != nil {
+			return "", err
+		}
+		if mode, ok := modes[path]; ok {
+			perm, err := mode.ToOSFileMode()
+			if err != nil {
+				return "", err
+			}
+			err = os.Chmod(filepath.Join(c.path, path), perm)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return "", err
+	}
+	if status.IsClean() {
+		head, err := c.repo.Head()
+		if err != nil {
+			return "", err
+		}
+		return head.Hash().String(), git.ErrNoStagedFiles
+	}
+	for file := range status {
+		_, err := wt.Add(file)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	opts := &extgogit.CommitOptions{
+		Author: &object.Signature{
+			Name:  info.Author.Name,
+			Email: info.Author.Email,
+			When:  time.Now(),
+		},
+	}
+	if c.signer != nil && c.signer.entity != nil {
+		opts.SignKey = c.signer.entity
+	}
+	hash, err := wt.Commit(info.Message, opts)
+	if err != nil {
+		return "", err
+	}
+	if c.signer != nil && c.signer.sshSigner != nil {
+		return signHeadSSH(c.repo, c.signer.sshSigner)
+	}
+	return hash.String(), nil
+}
+
+func writeFile(fs billy.Filesystem, path string, content io.Reader) error {
+	f, err := fs.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, content)
+	return err
+}
+
+// Push pushes the current HEAD to the remote unless refspecs are configured.
+// HEAD can be used as the source of the refspecs.
+func (c *GitClient) Push(ctx context.Context, cfg repository.PushConfig) error {
+	switch c.readOnly {
+	case readOnlyError:
+		return ErrReadOnly
+	case readOnlySkip:
+		tflog.Warn(ctx, "Skipping push as the provider is configured as read only", map[string]interface{}{"url": redactURLCredentials(c.url.String())})
+		return nil
+	}
+
+	// Credentials may have expired while cloning and committing.
+	err := c.credentialHelper.Fill(ctx, c.url, c.authOpts)
+	if err != nil {
+		return err
+	}
+	authMethod, err := transportAuth(c.authOpts)
+	if err != nil {
+		return fmt.Errorf("failed to construct auth method with options: %w", err)
+	}
+
+	head, err := c.repo.Head()
+	if err != nil {
+		return err
+	}
+	refspecs := []config.RefSpec{}
+	for _, ref := range cfg.Refspecs {
+		refspec := config.RefSpec(ref)
+		err := refspec.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid refspec %q: %w", ref, err)
+		}
+		// go-git does not resolve HEAD when used as the source of a refspec.
+		if refspec.Src() == "HEAD" {
+			force := ""
+			if refspec.IsForceUpdate() {
+				force = "+"
+			}
+			refspec = config.RefSpec(fmt.Sprintf("%s%s:%s", force, head.Name(), refspec.Dst(head.Name())))
+		}
+		refspecs = append(refspecs, refspec)
+	}
+	if len(refspecs) == 0 {
+		refspec := config.RefSpec(fmt.Sprintf("%s:%[1]s", head.Name()))
+		if c.gerrit {
+			// Gerrit creates a review for commits pushed to the magic refs/for namespace.
+			refspec = config.RefSpec(fmt.Sprintf("%s:refs/for/%s", head.Name(), head.Name().Short()))
+		}
+		refspecs = append(refspecs, refspec)
+	}
+
+	// go-git always sends push options as key=value pairs, and only a single
+	// value for each key, which is why duplicate keys are rejected when
+	// validating the configuration.
+	pushOptions := map[string]string{}
+	for _, option := range c.pushOptions {
+		k, v, _ := strings.Cut(option, "=")
+		pushOptions[k] = v
+	}
+
+	// Either all refs or none are updated when pushing multiple refs.
+	atomic := len(refspecs) > 1
+
+	err = c.operations.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.operations.Release()
+	ctx = withHTTPTransport(ctx, c.httpTransport)
+	return c.repo.PushContext(ctx, &extgogit.PushOptions{
+		RefSpecs:     refspecs,
+		Force:        cfg.Force,
+		RemoteName:   extgogit.DefaultRemoteName,
+		Auth:         authMethod,
+		ProxyOptions: c.proxy,
+		Options:      pushOptions,
+		Atomic:       atomic,
+	})
+}
+
+func transportAuth(opts *git.AuthOptions) (transport.AuthMethod, error) {
+	switch opts.Transport {
+	case git.HTTP, git.HTTPS:
+		if opts.Username != "" || opts.Password != "" {
+			return &githttp.BasicAuth{
+				Username: opts.Username,
+				Password: opts.Password,
+			}, nil
+		}
+		if opts.BearerToken != "" {
+			return &githttp.TokenAuth{
+				Token: opts.BearerToken,
+			}, nil
+		}
+		return nil, nil
+	case git.SSH:
+		pk, err := ssh.NewPublicKeys(opts.Username, opts.Identity, opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		if len(opts.KnownHosts) > 0 {
+			callback, err := knownhosts.New(opts.KnownHosts)
+			if err != nil {
+				return nil, err
+			}
+			pk.HostKeyCallback = callback
+		}
+		return pk, nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q", opts.Transport)
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+const defaultAttributesPath = ".gitattributes"
+
+// attributeRegex matches set, unset, unspecified and value attributes, whose
+// names may contain letters, digits, dashes, underscores and dots.
+var attributeRegex = regexp.MustCompile(`^(?:[-!]?[A-Za-z0-9_.][-A-Za-z0-9_.]*|[A-Za-z0-9_.][-A-Za-z0-9_.]*=\S*)$`)
+
+// attributesLine returns the line of the pattern and attributes.
+func attributesLine(pattern string, attributes []string) string {
+	return strings.Join(append([]string{pattern}, attributes...), " ")
+}
+
+// indexOfAttributesLine returns the index of the line with the same pattern
+// and attributes, ignoring the whitespace between them, or -1 when there is
+// none.
+func indexOfAttributesLine(lines []string, line string) int {
+	fields := strings.Join(strings.Fields(line), " ")
+	for i, l := range lines {
+		if strings.Join(strings.Fields(l), " ") == fields {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasAttributesLine returns true when the attributes file contains the line.
+func hasAttributesLine(content, line string) bool {
+	return indexOfAttributesLine(strings.Split(content, "\n"), line) != -1
+}
+
+// addAttributesLine appends the line to the attributes file, or returns the
+// content as is when it already contains the line.
+func addAttributesLine(content, line string) string {
+	if hasAttributesLine(content, line) {
+		return content
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + line + "\n"
+}
+
+// removeAttributesLine removes the line from the attributes file.
+func removeAttributesLine(content, line string) string {
+	lines := strings.SplitAfter(content, "\n")
+	i := indexOfAttributesLine(lines, line)
+	if i == -1 {
+		return content
+	}
+	return strings.Join(append(lines[:i], lines[i+1:]...), "")
+}
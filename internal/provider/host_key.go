@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/fluxcd/flux2/pkg/manifestgen/sourcesecret"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
+)
+
+// hostKeys caches the scanned host keys for the lifetime of the provider
+// process, so that each host is only scanned once instead of by every
+// operation.
+var hostKeys = &hostKeyCache{entries: map[string]*hostKeyEntry{}}
+
+type hostKeyCache struct {
+	mu      sync.Mutex
+	entries map[string]*hostKeyEntry
+}
+
+type hostKeyEntry struct {
+	once sync.Once
+	key  []byte
+	err  error
+}
+
+// Get returns the host key of the SSH server, scanning it when it is not
+// cached. Failed scans are not cached.
+func (c *hostKeyCache) Get(host string, proxyOpts transport.ProxyOptions) ([]byte, error) {
+	k := host + " " + proxyOpts.URL
+	c.mu.Lock()
+	e, ok := c.entries[k]
+	if !ok {
+		e = &hostKeyEntry{}
+		c.entries[k] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.key, e.err = scanHostKey(host, proxyOpts)
+	})
+	if e.err != nil {
+		c.mu.Lock()
+		if c.entries[k] == e {
+			delete(c.entries, k)
+		}
+		c.mu.Unlock()
+	}
+	return e.key, e.err
+}
+
+// knownHosts returns the configured known hosts, or the scanned host key of
+// the SSH server when none are configured and scanning is allowed.
+func knownHosts(u *url.URL, s *Ssh) ([]byte, error) {
+	if kh := s.KnownHosts.ValueString(); kh != "" {
+		return []byte(kh), nil
+	}
+	if s.OfflineKnownHosts.ValueBool() {
+		return nil, fmt.Errorf("host key of %s cannot be scanned as offline_known_hosts is set, known_hosts has to be configured", u.Host)
+	}
+	return hostKeys.Get(u.Host, getProxyOpts(u, s))
+}
+
+// scanHostKey returns the host key of the SSH server in known hosts format.
+// The connection is made through the proxy when one is configured.
+func scanHostKey(host string, proxyOpts transport.ProxyOptions) ([]byte, error) {
+	if proxyOpts.URL == "" {
+		return sourcesecret.ScanHostKey(host)
+	}
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	proxyURL, err := proxyOpts.FullURL()
+	if err != nil {
+		return nil, err
+	}
+	dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialer.Dial("tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("SSH key scan for host %s failed, error: %w", host, err)
+	}
+	defer conn.Close()
+	err = conn.SetDeadline(time.Now().Add(30 * time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	var hostKey []byte
+	config := &ssh.ClientConfig{
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = []byte(knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key))
+			// Abort the handshake as only the host key is needed.
+			return fmt.Errorf("host key scanned")
+		},
+	}
+	_, _, _, err = ssh.NewClientConn(conn, host, config)
+	if hostKey == nil {
+		return nil, fmt.Errorf("SSH key scan for host %s failed, error: %w", host, err)
+	}
+	return hostKey, nil
+}
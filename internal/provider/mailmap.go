@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+const mailmapPath = ".mailmap"
+
+// mailmapRegex matches the mailmap line forms, where the second identity is
+// the commit identity when it is set.
+var mailmapRegex = regexp.MustCompile(`^\s*([^<#]*?)\s*<([^>]*)>\s*(?:([^<#]*?)\s*<([^>]*)>)?\s*(?:#.*)?$`)
+
+// mailmapEntry maps the commit identity to the proper name and email.
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+	commitEmail string
+}
+
+// String returns the mailmap line of the entry.
+func (e mailmapEntry) String() string {
+	parts := []string{}
+	if e.properName != "" {
+		parts = append(parts, e.properName)
+	}
+	if e.properEmail != "" {
+		parts = append(parts, "<"+e.properEmail+">")
+	}
+	if e.commitName != "" {
+		parts = append(parts, e.commitName)
+	}
+	return strings.Join(append(parts, "<"+e.commitEmail+">"), " ")
+}
+
+// sameCommitIdentity returns true when the entries map the same commit
+// identity, which is compared case insensitively like git does.
+func (e mailmapEntry) sameCommitIdentity(o mailmapEntry) bool {
+	return strings.EqualFold(e.commitEmail, o.commitEmail) && strings.EqualFold(e.commitName, o.commitName)
+}
+
+func parseMailmapLine(line string) (mailmapEntry, bool) {
+	m := mailmapRegex.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+	if m == nil {
+		return mailmapEntry{}, false
+	}
+	if m[4] == "" {
+		return mailmapEntry{properName: m[1], commitEmail: m[2]}, true
+	}
+	return mailmapEntry{properName: m[1], properEmail: m[2], commitName: m[3], commitEmail: m[4]}, true
+}
+
+// findMailmapEntry returns the index of the line mapping the commit identity
+// of the entry, or -1 when there is none.
+func findMailmapEntry(lines []string, entry mailmapEntry) int {
+	for i, l := range lines {
+		e, ok := parseMailmapLine(l)
+		if ok && e.sameCommitIdentity(entry) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasMailmapEntry returns true when the mailmap maps the commit identity of the
+// entry to its proper name and email.
+func hasMailmapEntry(content string, entry mailmapEntry) bool {
+	lines := strings.Split(content, "\n")
+	i := findMailmapEntry(lines, entry)
+	if i == -1 {
+		return false
+	}
+	e, _ := parseMailmapLine(lines[i])
+	return e.properName == entry.properName && e.properEmail == entry.properEmail
+}
+
+// setMailmapEntry replaces the line mapping the commit identity of the entry,
+// or appends the entry when the commit identity is not mapped.
+func setMailmapEntry(content string, entry mailmapEntry) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if strings.TrimSpace(content) == "" {
+		lines = []string{}
+	}
+	i := findMailmapEntry(lines, entry)
+	if i == -1 {
+		lines = append(lines, entry.String())
+	} else {
+		lines[i] = entry.String()
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// removeMailmapEntry removes the line mapping the commit identity of the entry.
+func removeMailmapEntry(content string, entry mailmapEntry) string {
+	lines := strings.SplitAfter(content, "\n")
+	i := findMailmapEntry(lines, entry)
+	if i == -1 {
+		return content
+	}
+	return strings.Join(append(lines[:i], lines[i+1:]...), "")
+}
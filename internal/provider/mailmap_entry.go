@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type MailmapEntryResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Branch      types.String   `tfsdk:"branch"`
+	ProperName  types.String   `tfsdk:"proper_name"`
+	ProperEmail types.String   `tfsdk:"proper_email"`
+	CommitName  types.String   `tfsdk:"commit_name"`
+	CommitEmail types.String   `tfsdk:"commit_email"`
+	CommitSHA   types.String   `tfsdk:"commit_sha"`
+	AuthorName  types.String   `tfsdk:"author_name"`
+	AuthorEmail types.String   `tfsdk:"author_email"`
+	Message     types.String   `tfsdk:"message"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+var _ resource.Resource = &MailmapEntryResource{}
+var _ resource.ResourceWithModifyPlan = &MailmapEntryResource{}
+var _ resource.ResourceWithValidateConfig = &MailmapEntryResource{}
+
+func NewMailmapEntryResource() resource.Resource {
+	return &MailmapEntryResource{}
+}
+
+type MailmapEntryResource struct {
+	prd *ProviderResourceData
+}
+
+func (r *MailmapEntryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mailmap_entry"
+}
+
+func (r *MailmapEntryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Mailmap entry resource, which maps a commit identity to a proper name and email in the `.mailmap` file at the root of the repository. An existing line mapping the same commit identity is replaced, and the other lines are left as is.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch to write the entry to. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"proper_name": schema.StringAttribute{
+				Description: "Proper name of the commit identity. At least one of `proper_name` and `proper_email` has to be set.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.SingleLine(),
+				},
+			},
+			"proper_email": schema.StringAttribute{
+				Description: "Proper email of the commit identity. At least one of `proper_name` and `proper_email` has to be set.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"commit_name": schema.StringAttribute{
+				Description: "Name used in commits. Only commits with both the name and email are mapped when set, otherwise all commits with the email are mapped. Requires `proper_email` to be set.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.SingleLine(),
+				},
+			},
+			"commit_email": schema.StringAttribute{
+				Description: "Email used in commits.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"commit_sha": schema.StringAttribute{
+				Description: "SHA of the commit which last wrote the entry, or of the head commit of the branch when the mailmap already contained the entry.",
+				Computed:    true,
+			},
+			"author_name": schema.StringAttribute{
+				Description: "Author name of the commit. Defaults to the provider commits author name.",
+				Optional:    true,
+			},
+			"author_email": schema.StringAttribute{
+				Description: "Author email of the commit. Defaults to the provider commits author email.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"message": schema.StringAttribute{
+				Description: "Commit message. Defaults to the provider commits message.",
+				Optional:    true,
+			},
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+func (r *MailmapEntryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+func (r *MailmapEntryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data MailmapEntryResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.ProperName.IsNull() && data.ProperEmail.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("proper_name"), "Missing Attribute", "At least one of proper_name and proper_email has to be set.")
+	}
+	if !data.CommitName.IsNull() && data.ProperEmail.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("proper_email"), "Missing Attribute", "The proper email has to be set when commit_name is set.")
+	}
+	for attr, v := range map[string]types.String{"proper_name": data.ProperName, "commit_name": data.CommitName} {
+		if strings.ContainsAny(v.ValueString(), "<>#") {
+			resp.Diagnostics.AddAttributeError(path.Root(attr), "Invalid Name", "Names in the mailmap cannot contain <, > or #.")
+		}
+	}
+}
+
+// ModifyPlan checks the branch against the protected branches, and the
+// mailmap against the path policy. The commit is only known when the proper
+// identity has not changed.
+func (r *MailmapEntryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.prd == nil {
+		return
+	}
+
+	var branch types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("branch"), &branch)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.prd.validateBranch(ctx, branch)...)
+	resp.Diagnostics.Append(r.prd.validatePath(path.Root("commit_email"), mailmapPath)...)
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state MailmapEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.ProperName.Equal(state.ProperName) && plan.ProperEmail.Equal(state.ProperEmail) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("commit_sha"), state.CommitSHA)...)
+	}
+}
+
+func (r *MailmapEntryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *MailmapEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	branch, err := r.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", r.prd.redact(err.Error()))
+		return
+	}
+	data.Branch = types.StringValue(branch)
+	err = r.write(ctx, createTimeout, data)
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Mailmap Error", err)
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s", branch, strings.TrimSpace(data.CommitName.ValueString()+" <"+data.CommitEmail.ValueString()+">")))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read removes the resource from the state when the mailmap does not map the
+// commit identity to the proper identity, so that the entry is written again.
+func (r *MailmapEntryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *MailmapEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	b, _, err := r.prd.ReadFile(ctx, data.Branch.ValueString(), mailmapPath)
+	if errors.Is(err, os.ErrNotExist) {
+		tflog.Warn(ctx, "Removing resource from state as the mailmap does not exist", map[string]interface{}{"branch": data.Branch.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Mailmap Read Error", err)
+		return
+	}
+	if !hasMailmapEntry(string(b), data.entry()) {
+		tflog.Warn(ctx, "Removing resource from state as the mailmap does not contain the entry", map[string]interface{}{"entry": data.entry().String()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+}
+
+// Update replaces the line of the commit identity when the proper identity has
+// changed.
+func (r *MailmapEntryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *MailmapEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if data.CommitSHA.IsUnknown() {
+		err := r.write(ctx, updateTimeout, data)
+		if err != nil {
+			r.prd.addGitError(&resp.Diagnostics, "Git Mailmap Error", err)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MailmapEntryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *MailmapEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	entry := data.entry()
+	_, err := r.prd.ApplyChange(ctx, deleteTimeout, data.Branch.ValueString(), commit, repository.PushConfig{}, fileChange{
+		path: mailmapPath,
+		edit: func(content string, exists bool) (string, error) {
+			if !exists {
+				return "", os.ErrNotExist
+			}
+			return removeMailmapEntry(content, entry), nil
+		},
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Mailmap Error", err)
+		return
+	}
+}
+
+// write sets the entry in the mailmap and sets the commit in the model.
+func (r *MailmapEntryResource) write(ctx context.Context, timeout time.Duration, data *MailmapEntryResourceModel) error {
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	entry := data.entry()
+	hash, err := r.prd.ApplyChange(ctx, timeout, data.Branch.ValueString(), commit, repository.PushConfig{}, fileChange{
+		path: mailmapPath,
+		edit: func(content string, exists bool) (string, error) {
+			return setMailmapEntry(content, entry), nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	data.CommitSHA = types.StringValue(hash)
+	return nil
+}
+
+func (m *MailmapEntryResourceModel) entry() mailmapEntry {
+	return mailmapEntry{
+		properName:  m.ProperName.ValueString(),
+		properEmail: m.ProperEmail.ValueString(),
+		commitName:  m.CommitName.ValueString(),
+		commitEmail: m.CommitEmail.ValueString(),
+	}
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+// mergeRequestPushOptionPrefix is the prefix of the push options which are
+// sent for a merge request.
+const mergeRequestPushOptionPrefix = "merge_request."
+
+// MergeRequest configures the GitLab merge request created when pushing to the
+// branch of the resource.
+type MergeRequest struct {
+	TargetBranch       types.String `tfsdk:"target_branch"`
+	Title              types.String `tfsdk:"title"`
+	Description        types.String `tfsdk:"description"`
+	RemoveSourceBranch types.Bool   `tfsdk:"remove_source_branch"`
+}
+
+func mergeRequestResourceAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Creates a GitLab merge request from the branch of the resource with push options, for target branches which do not allow direct pushes. The branch is created from the target branch when it does not exist, and files are read from the target branch once the branch has been merged and removed. Requires `branch` to be set to a branch other than the target branch. Cannot be combined with `merge_request.*` push options of the provider or the resource.",
+		Attributes: map[string]schema.Attribute{
+			"target_branch": schema.StringAttribute{
+				Description: "Branch which the merge request targets. Defaults to the default branch of the remote repository.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"title": schema.StringAttribute{
+				Description: "Title of the merge request. Defaults to the commit message.",
+				Optional:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the merge request, which cannot contain newlines.",
+				Optional:    true,
+			},
+			"remove_source_branch": schema.BoolAttribute{
+				Description: "Removes the branch of the resource when the merge request is merged.",
+				Optional:    true,
+			},
+		},
+		Optional: true,
+	}
+}
+
+// validateMergeRequest returns errors for merge request options which cannot
+// be sent as push options, or which the push options of the resource would
+// conflict with.
+func validateMergeRequest(ctx context.Context, mr *MergeRequest, branch types.String, refspecs types.List, pushOptions types.List) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if mr == nil {
+		return diags
+	}
+	if !pushOptions.IsNull() && !pushOptions.IsUnknown() {
+		options := []types.String{}
+		diags.Append(pushOptions.ElementsAs(ctx, &options, false)...)
+		for i, option := range options {
+			if strings.HasPrefix(option.ValueString(), mergeRequestPushOptionPrefix) {
+				diags.AddAttributeError(path.Root("push_options").AtListIndex(i), "Invalid Attribute Combination", "merge_request.* push options cannot be set together with merge_request.")
+			}
+		}
+	}
+	for name, v := range map[string]types.String{"title": mr.Title, "description": mr.Description} {
+		if strings.ContainsAny(v.ValueString(), "\r\n") {
+			diags.AddAttributeError(path.Root("merge_request").AtName(name), "Invalid Merge Request", "Push options cannot contain newlines.")
+		}
+	}
+	if !refspecs.IsNull() {
+		diags.AddAttributeError(path.Root("merge_request"), "Invalid Attribute Combination", "merge_request cannot be set together with refspecs.")
+	}
+	// The provider branch or the default branch of the remote repository may be
+	// the target branch, which the commit would then be pushed to directly.
+	if branch.IsNull() {
+		diags.AddAttributeError(path.Root("branch"), "Invalid Attribute Combination", "branch must be set when merge_request is set.")
+	}
+	if !mr.TargetBranch.IsNull() && !branch.IsUnknown() && mr.TargetBranch.Equal(branch) {
+		diags.AddAttributeError(path.Root("merge_request").AtName("target_branch"), "Invalid Merge Request", "The target branch must differ from the branch of the resource.")
+	}
+	return diags
+}
+
+// targetBranch returns the branch which the merge request targets.
+func (mr *MergeRequest) targetBranch(ctx context.Context, prd *ProviderResourceData) (string, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if mr.TargetBranch.ValueString() != "" {
+		return mr.TargetBranch.ValueString(), diags
+	}
+	target, err := prd.RemoteDefaultBranch(ctx)
+	if err != nil {
+		diags.AddAttributeError(path.Root("merge_request").AtName("target_branch"), "Git Branch Error", prd.redact(err.Error()))
+		return "", diags
+	}
+	return target, diags
+}
+
+// withMergeRequest returns resource data which creates the branch from the
+// target branch when it does not exist, and sends the push options which
+// create a merge request on GitLab. It fails when the branch which is pushed
+// to is the target branch, as no merge request would be created.
+func (m *RepositoryFileResourceModel) withMergeRequest(ctx context.Context, prd *ProviderResourceData, branch string) (*ProviderResourceData, diag.Diagnostics) {
+	mr := m.MergeRequest
+	if mr == nil {
+		return prd, nil
+	}
+	target, diags := mr.targetBranch(ctx, prd)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if branch == target {
+		diags.AddAttributeError(path.Root("merge_request").AtName("target_branch"), "Invalid Merge Request", fmt.Sprintf("The target branch %s must differ from the branch of the resource.", target))
+		return nil, diags
+	}
+	for _, option := range prd.pushOptions {
+		if strings.HasPrefix(option, mergeRequestPushOptionPrefix) {
+			diags.AddAttributeError(path.Root("merge_request"), "Invalid Attribute Combination", fmt.Sprintf("The push option %s conflicts with the push options sent for merge_request.", option))
+			return nil, diags
+		}
+	}
+	title := mr.Title.ValueString()
+	if title == "" {
+		title = strings.SplitN(strings.TrimSpace(m.Message.ValueString()), "\n", 2)[0]
+	}
+	pushOptions := append([]string{}, prd.pushOptions...)
+	pushOptions = append(pushOptions, "merge_request.create=true", "merge_request.target="+target)
+	if title != "" {
+		pushOptions = append(pushOptions, "merge_request.title="+title)
+	}
+	if mr.Description.ValueString() != "" {
+		pushOptions = append(pushOptions, "merge_request.description="+mr.Description.ValueString())
+	}
+	if mr.RemoveSourceBranch.ValueBool() {
+		pushOptions = append(pushOptions, "merge_request.remove_source_branch=true")
+	}
+	return prd.WithBaseBranch(target).WithPushOptions(pushOptions), diags
+}
+
+// readBranch returns the branch which the file is read from. Files are read
+// from the target branch of the merge request once the branch has been merged
+// and removed.
+func (m *RepositoryFileResourceModel) readBranch(ctx context.Context, prd *ProviderResourceData) (string, diag.Diagnostics) {
+	branch := m.Branch.ValueString()
+	if m.MergeRequest == nil {
+		return branch, nil
+	}
+	head, err := prd.RemoteHead(ctx, branch)
+	if err != nil {
+		diags := diag.Diagnostics{}
+		prd.addGitError(&diags, "Git Branch Error", err)
+		return "", diags
+	}
+	if head != "" {
+		return branch, nil
+	}
+	return m.MergeRequest.targetBranch(ctx, prd)
+}
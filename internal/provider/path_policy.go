@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ErrPathNotAllowed is returned when writing a path which is not allowed by
+// the path policy of the provider.
+var ErrPathNotAllowed = errors.New("path is not allowed by the provider path policy")
+
+// pathPolicy confines the paths which resources can write to.
+type pathPolicy struct {
+	allowed gitignore.Matcher
+	denied  gitignore.Matcher
+}
+
+func newPathPolicy(ctx context.Context, allowed, denied types.List) (*pathPolicy, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if allowed.IsNull() && denied.IsNull() {
+		return nil, diags
+	}
+	policy := &pathPolicy{}
+	for name, list := range map[string]types.List{"allowed_paths": allowed, "denied_paths": denied} {
+		if list.IsNull() || list.IsUnknown() {
+			continue
+		}
+		values := []string{}
+		diags.Append(list.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		patterns := []gitignore.Pattern{}
+		for _, v := range values {
+			patterns = append(patterns, gitignore.ParsePattern(v, nil))
+		}
+		if name == "allowed_paths" {
+			policy.allowed = gitignore.NewMatcher(patterns)
+		} else {
+			policy.denied = gitignore.NewMatcher(patterns)
+		}
+	}
+	return policy, diags
+}
+
+// check returns an error when the path of the repository is not matched by
+// the allowed patterns, or is matched by the denied patterns.
+func (p *pathPolicy) check(repoPath string) error {
+	if p == nil {
+		return nil
+	}
+	if p.allowed != nil && !matchPath(p.allowed, repoPath) {
+		return fmt.Errorf("%w: %s is outside of the allowed paths", ErrPathNotAllowed, repoPath)
+	}
+	if p.denied != nil && matchPath(p.denied, repoPath) {
+		return fmt.Errorf("%w: %s matches the denied paths", ErrPathNotAllowed, repoPath)
+	}
+	return nil
+}
+
+// matchPath returns true when the path or one of its parent directories is
+// matched, so that directory patterns match the files within them like in
+// .gitignore files.
+func matchPath(m gitignore.Matcher, p string) bool {
+	parts := strings.Split(treePath(p), "/")
+	for i := 1; i <= len(parts); i++ {
+		if m.Match(parts[:i], i < len(parts)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePath returns an error diagnostic for the attribute when the path of
+// the repository is not allowed by the path policy.
+func (prd *ProviderResourceData) validatePath(attr path.Path, repoPath string) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	err := prd.pathPolicy.check(repoPath)
+	if err != nil {
+		diags.AddAttributeError(attr, "Path Not Allowed", err.Error())
+	}
+	return diags
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// PathWarnings returns warnings about writing the file to the branch, when
+// the path is ignored by the .gitignore files of the branch or has attributes
+// in the .gitattributes files which make committing the file a likely mistake.
+func (prd *ProviderResourceData) PathWarnings(ctx context.Context, branch, p string) ([]string, error) {
+	warnings := []string{}
+	err := prd.withHeadRepository(ctx, branch, p, func(repo *extgogit.Repository) error {
+		commit, err := headCommit(repo)
+		if err != nil || commit == nil {
+			return err
+		}
+		warnings, err = pathWarnings(commit, treePath(p))
+		return err
+	})
+	// There are no rules in empty repositories.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return warnings, err
+}
+
+func pathWarnings(commit *object.Commit, p string) ([]string, error) {
+	parts := strings.Split(p, "/")
+	ignorePatterns := []gitignore.Pattern{}
+	attributes := []gitattributes.MatchAttribute{}
+	// The rules of the repository root come first, as the rules of the
+	// directories closer to the file take precedence.
+	for i := 0; i < len(parts); i++ {
+		domain := parts[:i]
+		b, err := readTreeFile(commit, path.Join(append(append([]string{}, domain...), ".gitignore")...))
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+				continue
+			}
+			ignorePatterns = append(ignorePatterns, gitignore.ParsePattern(line, domain))
+		}
+		b, err = readTreeFile(commit, path.Join(append(append([]string{}, domain...), ".gitattributes")...))
+		if err != nil {
+			return nil, err
+		}
+		attrs, err := gitattributes.ReadAttributes(bytes.NewReader(b), domain, i == 0)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse .gitattributes in %q: %w", path.Join(domain...), err)
+		}
+		attributes = append(attributes, attrs...)
+	}
+
+	warnings := []string{}
+	ignoreMatcher := gitignore.NewMatcher(ignorePatterns)
+	ignored := ignoreMatcher.Match(parts, false)
+	for i := 1; i < len(parts) && !ignored; i++ {
+		ignored = ignoreMatcher.Match(parts[:i], true)
+	}
+	if ignored {
+		warnings = append(warnings, fmt.Sprintf("Path %s is ignored by the .gitignore files of the repository, so changes to the file are easily missed.", p))
+	}
+	results, _ := gitattributes.NewMatcher(attributes).Match(parts, []string{"export-ignore", "filter"})
+	if attr, ok := results["export-ignore"]; ok && attr.IsSet() {
+		warnings = append(warnings, fmt.Sprintf("Path %s has the export-ignore attribute, so the file is left out of archives of the repository.", p))
+	}
+	if attr, ok := results["filter"]; ok && attr.IsValueSet() && attr.Value() == "lfs" {
+		warnings = append(warnings, fmt.Sprintf("Path %s is tracked by Git LFS, but the content is committed as a regular file instead of an LFS pointer.", p))
+	}
+	return warnings, nil
+}
+
+// readTreeFile returns the content of the file in the tree of the commit, or
+// nil when the file does not exist.
+func readTreeFile(commit *object.Commit, p string) ([]byte, error) {
+	file, err := commit.File(p)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// phaseTimeouts limit the time of the clone and push phases of an operation,
+// so that a slow clone does not use up the time left to retry the push.
+type phaseTimeouts struct {
+	clone time.Duration
+	push  time.Duration
+}
+
+func newPhaseTimeouts(clone, push types.String) (phaseTimeouts, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	timeouts := phaseTimeouts{}
+	for attr, timeout := range map[string]struct {
+		value types.String
+		d     *time.Duration
+	}{
+		"clone_timeout": {clone, &timeouts.clone},
+		"push_timeout":  {push, &timeouts.push},
+	} {
+		if timeout.value.IsNull() || timeout.value.IsUnknown() {
+			continue
+		}
+		d, err := time.ParseDuration(timeout.value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root(attr), "Invalid Duration", err.Error())
+			continue
+		}
+		if d <= 0 {
+			diags.AddAttributeError(path.Root(attr), "Invalid Duration", "Value has to be larger than zero.")
+			continue
+		}
+		*timeout.d = d
+	}
+	return timeouts, diags
+}
+
+// withPhaseTimeout returns a context which is cancelled after the timeout of
+// the phase, or when the context of the operation is done. The timeout is not
+// limited when it is zero.
+func withPhaseTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// phaseError returns an error describing that the phase timed out when the
+// phase context, but not the context of the operation, has expired.
+func phaseError(ctx, phaseCtx context.Context, phase string, timeout time.Duration, err error) error {
+	if err == nil || ctx.Err() != nil || !errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%s timed out after %s: %w", phase, timeout, context.DeadlineExceeded)
+}
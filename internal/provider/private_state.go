@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	fileHeadKey  = "head"
+	importRefKey = "import"
+)
+
+// privateState is implemented by the private state of resource requests and
+// responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// fileHead is the branch head at which a file was last written or read. The
+// file cannot have changed as long as the branch head is the same. Written is
+// the blob last written by Terraform, which differs from the blob of the file
+// when it was modified elsewhere.
+type fileHead struct {
+	URL     string `json:"url"`
+	Path    string `json:"path"`
+	Hash    string `json:"hash"`
+	Written string `json:"written,omitempty"`
+}
+
+func getFileHead(ctx context.Context, p privateState) (fileHead, diag.Diagnostics) {
+	b, diags := p.GetKey(ctx, fileHeadKey)
+	if diags.HasError() || b == nil {
+		return fileHead{}, diags
+	}
+	head := fileHead{}
+	err := json.Unmarshal(b, &head)
+	if err != nil {
+		diags.AddError("Invalid Private State", err.Error())
+	}
+	return head, diags
+}
+
+func setFileHead(ctx context.Context, p privateState, head fileHead) diag.Diagnostics {
+	b, err := json.Marshal(head)
+	if err != nil {
+		diags := diag.Diagnostics{}
+		diags.AddError("Invalid Private State", err.Error())
+		return diags
+	}
+	return p.SetKey(ctx, fileHeadKey, b)
+}
+
+// getImportRef returns the ref of a file imported from a structured ID, or nil
+// when the file was imported from a branch:path ID.
+func getImportRef(ctx context.Context, p privateState) (*string, diag.Diagnostics) {
+	b, diags := p.GetKey(ctx, importRefKey)
+	if diags.HasError() || b == nil {
+		return nil, diags
+	}
+	ref := ""
+	err := json.Unmarshal(b, &ref)
+	if err != nil {
+		diags.AddError("Invalid Private State", err.Error())
+	}
+	return &ref, diags
+}
+
+func setImportRef(ctx context.Context, p privateState, ref string) diag.Diagnostics {
+	b, err := json.Marshal(ref)
+	if err != nil {
+		diags := diag.Diagnostics{}
+		diags.AddError("Invalid Private State", err.Error())
+		return diags
+	}
+	return p.SetKey(ctx, importRefKey, b)
+}
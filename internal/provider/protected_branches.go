@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ErrProtectedBranch is returned when writing to a branch which is protected by
+// the provider.
+var ErrProtectedBranch = errors.New("branch is protected by the provider")
+
+func newProtectedBranches(ctx context.Context, patterns types.List) ([]string, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if patterns.IsNull() || patterns.IsUnknown() {
+		return nil, diags
+	}
+	values := []string{}
+	diags.Append(patterns.ElementsAs(ctx, &values, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	for i, pattern := range values {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			diags.AddAttributeError(tfpath.Root("protected_branches").AtListIndex(i), "Invalid Branch Pattern", fmt.Sprintf("Pattern %q is not valid: %s", pattern, err))
+		}
+	}
+	return values, diags
+}
+
+// checkBranch returns an error when the branch matches one of the protected
+// branch patterns.
+func (prd *ProviderResourceData) checkBranch(branch string) error {
+	for _, pattern := range prd.protectedBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return fmt.Errorf("%w: %s matches %s", ErrProtectedBranch, branch, pattern)
+		}
+	}
+	return nil
+}
+
+// validateBranch returns an error diagnostic for the branch attribute when the
+// branch which is written to is protected. Branches which cannot be resolved
+// when planning are checked before committing instead.
+func (prd *ProviderResourceData) validateBranch(ctx context.Context, branch types.String) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if len(prd.protectedBranches) == 0 {
+		return diags
+	}
+	if branch.IsUnknown() {
+		branch = types.StringNull()
+	}
+	resolved, err := prd.ResolveBranch(ctx, branch)
+	if err != nil {
+		return diags
+	}
+	err = prd.checkBranch(resolved)
+	if err != nil {
+		diags.AddAttributeError(tfpath.Root("branch"), "Protected Branch", err.Error())
+	}
+	return diags
+}
@@ -2,34 +2,118 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
 )
 
 type Ssh struct {
-	Username   types.String `tfsdk:"username"`
-	Password   types.String `tfsdk:"password"`
-	PrivateKey types.String `tfsdk:"private_key"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	PrivateKey        types.String `tfsdk:"private_key"`
+	ProxyUrl          types.String `tfsdk:"proxy_url"`
+	KnownHosts        types.String `tfsdk:"known_hosts"`
+	OfflineKnownHosts types.Bool   `tfsdk:"offline_known_hosts"`
 }
 
 type Http struct {
-	Username             types.String `tfsdk:"username"`
-	Password             types.String `tfsdk:"password"`
-	InsecureHttpAllowed  types.Bool   `tfsdk:"allow_insecure_http"`
-	CertificateAuthority types.String `tfsdk:"certificate_authority"`
+	Username                 types.String `tfsdk:"username"`
+	Password                 types.String `tfsdk:"password"`
+	InsecureHttpAllowed      types.Bool   `tfsdk:"allow_insecure_http"`
+	CertificateAuthority     types.String `tfsdk:"certificate_authority"`
+	CertificateAuthorityFile types.String `tfsdk:"certificate_authority_file"`
+	ClientCertificate        types.String `tfsdk:"client_certificate"`
+	ClientKey                types.String `tfsdk:"client_key"`
+	InsecureSkipVerify       types.Bool   `tfsdk:"insecure_skip_tls_verify"`
+	TLSMinVersion            types.String `tfsdk:"tls_min_version"`
+	TLSCipherSuites          types.List   `tfsdk:"tls_cipher_suites"`
+	UserAgent                types.String `tfsdk:"user_agent"`
+	Headers                  types.Map    `tfsdk:"headers"`
+}
+
+type CredentialHelper struct {
+	Command types.String `tfsdk:"command"`
+	Args    types.List   `tfsdk:"args"`
+	Ttl     types.String `tfsdk:"ttl"`
+}
+
+type Commits struct {
+	AuthorName  types.String `tfsdk:"author_name"`
+	AuthorEmail types.String `tfsdk:"author_email"`
+	Message     types.String `tfsdk:"message"`
+}
+
+type Signing struct {
+	Format     types.String `tfsdk:"format"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	Passphrase types.String `tfsdk:"passphrase"`
+}
+
+type VerifyHead struct {
+	OpenPGPPublicKeys types.String `tfsdk:"openpgp_public_keys"`
+	SSHPublicKeys     types.List   `tfsdk:"ssh_public_keys"`
+}
+
+type Clone struct {
+	Shallow      types.Bool  `tfsdk:"shallow"`
+	Depth        types.Int64 `tfsdk:"depth"`
+	SingleBranch types.Bool  `tfsdk:"single_branch"`
+	NoTags       types.Bool  `tfsdk:"no_tags"`
+	NoCheckout   types.Bool  `tfsdk:"no_checkout"`
+}
+
+type Telemetry struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Insecure types.Bool   `tfsdk:"insecure"`
+	Headers  types.Map    `tfsdk:"headers"`
 }
 
 type GitProviderModel struct {
-	Url  types.String `tfsdk:"url"`
-	Ssh  *Ssh         `tfsdk:"ssh"`
-	Http *Http        `tfsdk:"http"`
+	Url                     types.String      `tfsdk:"url"`
+	Ssh                     *Ssh              `tfsdk:"ssh"`
+	Http                    *Http             `tfsdk:"http"`
+	CredentialHelper        *CredentialHelper `tfsdk:"credential_helper"`
+	MaxConcurrentOperations types.Int64       `tfsdk:"max_concurrent_operations"`
+	MaxConcurrentClones     types.Int64       `tfsdk:"max_concurrent_clones"`
+	ValidateConnection      types.Bool        `tfsdk:"validate_connection"`
+	ReadOnly                types.String      `tfsdk:"read_only"`
+	AzureDevOps             types.Bool        `tfsdk:"azure_devops"`
+	Gerrit                  types.Bool        `tfsdk:"gerrit"`
+	PathPrefix              types.String      `tfsdk:"path_prefix"`
+	AllowedPaths            types.List        `tfsdk:"allowed_paths"`
+	DeniedPaths             types.List        `tfsdk:"denied_paths"`
+	WebUrlTemplate          types.String      `tfsdk:"web_url_template"`
+	Branch                  types.String      `tfsdk:"branch"`
+	ProtectedBranches       types.List        `tfsdk:"protected_branches"`
+	Commits                 *Commits          `tfsdk:"commits"`
+	Signing                 *Signing          `tfsdk:"signing"`
+	VerifyHead              *VerifyHead       `tfsdk:"verify_head"`
+	PushOptions             types.List        `tfsdk:"push_options"`
+	WorkDir                 types.String      `tfsdk:"work_dir"`
+	KeepTempDirs            types.Bool        `tfsdk:"keep_temp_dirs"`
+	Clone                   *Clone            `tfsdk:"clone"`
+	BatchCommits            types.Bool        `tfsdk:"batch_commits"`
+	BatchWindow             types.String      `tfsdk:"batch_window"`
+	CloneTimeout            types.String      `tfsdk:"clone_timeout"`
+	PushTimeout             types.String      `tfsdk:"push_timeout"`
+	RetryableErrors         types.List        `tfsdk:"retryable_errors"`
+	AuditLog                types.String      `tfsdk:"audit_log"`
+	Telemetry               *Telemetry        `tfsdk:"telemetry"`
 }
 
 var _ provider.Provider = &GitProvider{}
+var _ provider.ProviderWithValidateConfig = &GitProvider{}
 
 type GitProvider struct {
 	version string
@@ -44,74 +128,447 @@ func (p *GitProvider) Schema(ctx context.Context, req provider.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"url": schema.StringAttribute{
-				Required: true,
+				Description: "Url of the Git repository using the `https`, `http`, `ssh` or anonymous `git` protocol. Scp-style urls such as `git@github.com:org/repo.git` are supported for SSH.",
+				Required:    true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Default branch used by resources which do not set a branch. The default branch of the remote repository is used when not set.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"protected_branches": schema.ListAttribute{
+				Description: "Branches which resources cannot write to, for example `main` or `release/*`. Writes to a matching branch fail when planning, or before committing when the branch is only known during the apply.",
+				ElementType: types.StringType,
+				Optional:    true,
 			},
-			"ssh": schema.SingleNestedAttribute{
+			"ssh":  providerConnectionAttribute(sshAttributes()),
+			"http": providerConnectionAttribute(httpAttributes()),
+			"credential_helper": schema.SingleNestedAttribute{
 				Attributes: map[string]schema.Attribute{
-					"username": schema.StringAttribute{
-						Description: "Username for Git SSH server.",
+					"command": schema.StringAttribute{
+						Description: "Command executed to obtain HTTP credentials using the git-credential protocol.",
+						Required:    true,
+					},
+					"args": schema.ListAttribute{
+						Description: "Arguments passed to the command, including the action such as `get`.",
+						ElementType: types.StringType,
 						Optional:    true,
 					},
-					"password": schema.StringAttribute{
-						Description: "Password for private key.",
+					"ttl": schema.StringAttribute{
+						Description: "Duration for which credentials are reused, for example `15m`. Credentials are resolved before every git operation when not set, unless the helper returns `password_expiry_utc`.",
 						Optional:    true,
-						Sensitive:   true,
+					},
+				},
+				Optional: true,
+			},
+			"commits": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"author_name": schema.StringAttribute{
+						Description: "Default author name of commits.",
+						Optional:    true,
+					},
+					"author_email": schema.StringAttribute{
+						Description: "Default author email of commits.",
+						Optional:    true,
+						Validators: []validator.String{
+							validators.Email(),
+						},
+					},
+					"message": schema.StringAttribute{
+						Description: "Default commit message.",
+						Optional:    true,
+					},
+				},
+				Optional: true,
+			},
+			"signing": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"format": schema.StringAttribute{
+						Description: "Format of the signing key, either `openpgp` or `ssh`. Defaults to `openpgp`.",
+						Optional:    true,
+						Validators: []validator.String{
+							validators.OneOf(signingFormatOpenPGP, signingFormatSSH),
+						},
 					},
 					"private_key": schema.StringAttribute{
-						Description: "Private key used for authenticating to the Git SSH server.",
+						Description: "Private key used to sign commits, either an ASCII armored OpenPGP key or an OpenSSH key depending on the format. It is separate from the SSH key used to authenticate.",
+						Required:    true,
+						Sensitive:   true,
+					},
+					"passphrase": schema.StringAttribute{
+						Description: "Passphrase of the private key.",
 						Optional:    true,
 						Sensitive:   true,
 					},
 				},
 				Optional: true,
 			},
-			"http": schema.SingleNestedAttribute{
+			"verify_head": schema.SingleNestedAttribute{
+				Description: "Verifies the signature of the head commit of the branch with the public keys before committing on top of it, so that commits are never added to a history which may have been tampered with. Branches without commits are not verified. Requires `signing` with a key which is among the trusted keys, as the commits of the provider would otherwise not be trusted.",
 				Attributes: map[string]schema.Attribute{
-					"username": schema.StringAttribute{
-						Description: "Username for basic authentication.",
+					"openpgp_public_keys": schema.StringAttribute{
+						Description: "ASCII armored OpenPGP public keys which are trusted to sign commits.",
 						Optional:    true,
 					},
-					"password": schema.StringAttribute{
-						Description: "Password for basic authentication.",
+					"ssh_public_keys": schema.ListAttribute{
+						Description: "SSH public keys in the authorized keys format which are trusted to sign commits, for example `ssh-ed25519 AAAA...`.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+				Optional: true,
+			},
+			"push_options": schema.ListAttribute{
+				Description: "Push options sent to the server when pushing, for example `ci.skip` or `merge_request.create` on GitLab. Options are sent as `key=value`, with an empty value when no value is given. Each key can only be given once, as go-git sends a single value for each key.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"max_concurrent_clones": schema.Int64Attribute{
+				Description: "Maximum number of git clone and fetch operations run concurrently, which limits the memory and disk used without limiting cheaper operations such as listing references. Clones and fetches are only limited by `max_concurrent_operations` when not set.",
+				Optional:    true,
+			},
+			"max_concurrent_operations": schema.Int64Attribute{
+				Description: "Maximum number of git clone and push operations run concurrently. Operations are not limited when not set.",
+				Optional:    true,
+			},
+			"validate_connection": schema.BoolAttribute{
+				Description: "Lists the remote references during provider configuration to validate the url and credentials.",
+				Optional:    true,
+			},
+			"azure_devops": schema.BoolAttribute{
+				Description: "Enables the multi_ack capability negotiation required by Azure DevOps, and clones the repository again instead of fetching into the existing clone when a push is rejected. The capability negotiation is shared by the provider process, so all provider configurations have to set the same value. Cannot be combined with `work_dir`.",
+				Optional:    true,
+			},
+			"gerrit": schema.BoolAttribute{
+				Description: "Pushes commits to `refs/for/<branch>` with a generated Change-Id trailer to create Gerrit reviews instead of updating the branch.",
+				Optional:    true,
+			},
+			"path_prefix": schema.StringAttribute{
+				Description: "Directory in the repository which all resource paths are relative to, for example `clusters/prod`.",
+				Optional:    true,
+			},
+			"allowed_paths": schema.ListAttribute{
+				Description: "Gitignore style patterns of the paths in the repository which resources can write to, for example `apps/team-a/`. Paths include the path prefix, and patterns matching a directory allow the files within it. Writing other paths fails when planning.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"denied_paths": schema.ListAttribute{
+				Description: "Gitignore style patterns of the paths in the repository which resources cannot write to, for example `apps/team-a/secrets/`. Denied paths take precedence over `allowed_paths`.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"web_url_template": schema.StringAttribute{
+				Description: "Template of the `web_url` of files, for hosts where the url is not known by the provider. The `{host}`, `{repository}`, `{branch}`, `{commit}` and `{path}` placeholders are replaced, for example `https://{host}/{repository}/blob/{commit}/{path}`.",
+				Optional:    true,
+			},
+			"clone": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"shallow": schema.BoolAttribute{
+						Description: "Only fetches the latest commit of the branch instead of the whole history.",
+						Optional:    true,
+					},
+					"depth": schema.Int64Attribute{
+						Description: "Number of commits fetched for shallow clones. Defaults to 1 when `shallow` is set, and implies a shallow clone when set.",
+						Optional:    true,
+					},
+					"single_branch": schema.BoolAttribute{
+						Description: "Only fetches the branch which is written to instead of all branches. Defaults to `true`.",
 						Optional:    true,
-						Sensitive:   true,
 					},
-					"allow_insecure_http": schema.BoolAttribute{
-						Description: "Allows http Git url connections.",
+					"no_tags": schema.BoolAttribute{
+						Description: "Skips fetching tags. Tags pointing into the fetched history are fetched when set to `false`. Defaults to `true`.",
 						Optional:    true,
 					},
-					"certificate_authority": schema.StringAttribute{
-						Description: "Certificate authority to validate self-signed certificates.",
+					"no_checkout": schema.BoolAttribute{
+						Description: "Clones a bare repository without checking out a worktree. Commits are created from the tree of the branch and the changed files, which avoids writing every file of large repositories to disk.",
 						Optional:    true,
 					},
 				},
 				Optional: true,
 			},
+			"batch_commits": schema.BoolAttribute{
+				Description: "Combines the file changes of resources applied concurrently into a single commit and push per repository and branch. Only changes with the same commit author, message and push configuration are combined.",
+				Optional:    true,
+			},
+			"batch_window": schema.StringAttribute{
+				Description: "Duration for which file changes are collected before they are committed when `batch_commits` is enabled. Defaults to `2s`.",
+				Optional:    true,
+			},
+			"clone_timeout": schema.StringAttribute{
+				Description: "Maximum duration of each clone or fetch of the repository, for example `2m`, including the time waiting for `max_concurrent_clones`. A clone which times out fails with a network error which is retried within the timeout of the resource operation, leaving time to retry the push. Clones are only limited by the resource timeouts when not set.",
+				Optional:    true,
+			},
+			"push_timeout": schema.StringAttribute{
+				Description: "Maximum duration of each push to the repository, for example `1m`, including the time waiting for `max_concurrent_operations`. Pushes are only limited by the resource timeouts when not set.",
+				Optional:    true,
+			},
+			"retryable_errors": schema.ListAttribute{
+				Description: "Classes of git errors which are retried until the timeout of the operation, out of `authentication`, `not_found`, `network`, `non_fast_forward`, `rate_limit` and `unknown`. Defaults to `network`, `non_fast_forward` and `rate_limit`.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"work_dir": schema.StringAttribute{
+				Description: "Directory used to cache working copies of the repository between operations and runs. Cached working copies are updated with a fetch instead of cloning the repository. The directory must not be shared by concurrent Terraform runs.",
+				Optional:    true,
+			},
+			"audit_log": schema.StringAttribute{
+				Description: "File which a JSON record of each clone, commit and push is appended to, with the repository, branch, paths, commit, duration and outcome of the operation. Records of the same Terraform operation share the `run` id.",
+				Optional:    true,
+			},
+			"telemetry": schema.SingleNestedAttribute{
+				Description: "Exports a trace span and metrics of each clone, commit and push over OTLP HTTP, including the duration, bytes transferred over HTTP, push retries and failures by error class. Remaining data is exported when Terraform stops the provider.",
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						Description: "Host and port of the OTLP HTTP endpoint, for example `otel-collector:4318`. Defaults to the `OTEL_EXPORTER_OTLP_ENDPOINT` environment variable, or `localhost:4318`.",
+						Optional:    true,
+					},
+					"insecure": schema.BoolAttribute{
+						Description: "Exports over HTTP instead of HTTPS.",
+						Optional:    true,
+					},
+					"headers": schema.MapAttribute{
+						Description: "Headers sent with the exported data, for example an authentication token of the collector.",
+						ElementType: types.StringType,
+						Optional:    true,
+						Sensitive:   true,
+					},
+				},
+				Optional: true,
+			},
+			"keep_temp_dirs": schema.BoolAttribute{
+				Description: "Keeps the temporary clones made when `work_dir` is not set instead of removing them after each operation, which can be useful when debugging.",
+				Optional:    true,
+			},
+			"read_only": schema.StringAttribute{
+				Description: "Prevents pushing to the repository. Pushes fail with an error when set to `error` and are logged and skipped when set to `skip`.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.OneOf(readOnlyError, readOnlySkip),
+				},
+			},
 		},
 	}
 }
 
+// ValidateConfig validates the repository configuration, so that mistakes such
+// as a missing ssh block are reported when validating instead of when git
+// operations are run.
+func (p *GitProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data GitProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateRepository(path.Empty(), data.Url, data.Ssh, data.Http)...)
+	resp.Diagnostics.Append(validatePushOptions(ctx, path.Root("push_options"), data.PushOptions)...)
+}
+
+// validatePushOptions returns a diagnostic when a key is given more than once,
+// as go-git sends the push options as a map which only keeps the last value
+// of each key.
+func validatePushOptions(ctx context.Context, p path.Path, pushOptions types.List) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if pushOptions.IsNull() || pushOptions.IsUnknown() {
+		return diags
+	}
+	options := []types.String{}
+	diags.Append(pushOptions.ElementsAs(ctx, &options, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	keys := map[string]bool{}
+	for i, option := range options {
+		if option.IsUnknown() {
+			continue
+		}
+		k, _, _ := strings.Cut(option.ValueString(), "=")
+		if keys[k] {
+			diags.AddAttributeError(p.AtListIndex(i), "Duplicate Push Option", fmt.Sprintf("Push option %q is given more than once, which is not supported as only one value can be sent for each key.", k))
+			continue
+		}
+		keys[k] = true
+	}
+	return diags
+}
+
 func (p *GitProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data GitProviderModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	resp.ResourceData = &ProviderResourceData{
-		url:  data.Url.ValueString(),
-		ssh:  data.Ssh,
-		http: data.Http,
+	credentialHelper, diags := newCredentialHelper(ctx, data.CredentialHelper)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !data.MaxConcurrentOperations.IsNull() && data.MaxConcurrentOperations.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_concurrent_operations"), "Invalid Value", "Value has to be larger than zero.")
+		return
+	}
+	if !data.MaxConcurrentClones.IsNull() && data.MaxConcurrentClones.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_concurrent_clones"), "Invalid Value", "Value has to be larger than zero.")
+		return
+	}
+	if data.Clone != nil && !data.Clone.Depth.IsNull() && data.Clone.Depth.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("clone").AtName("depth"), "Invalid Value", "Value has to be larger than zero.")
+		return
+	}
+	if data.AzureDevOps.ValueBool() {
+		if data.WorkDir.ValueString() != "" {
+			resp.Diagnostics.AddAttributeError(path.Root("work_dir"), "Invalid Value", "Work directory cannot be used with Azure DevOps as fetching into an existing repository is not supported.")
+			return
+		}
+	}
+	if err := setAzureDevOps(data.AzureDevOps.ValueBool()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("azure_devops"), "Invalid Value", err.Error())
+		return
+	}
+	httpTransport, err := newHTTPTransport(data.Http)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("http"), "Invalid HTTP Configuration", err.Error())
+		return
 	}
+	resp.Diagnostics.Append(validatePushOptions(ctx, path.Root("push_options"), data.PushOptions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pushOptions := []string{}
+	if !data.PushOptions.IsNull() && !data.PushOptions.IsUnknown() {
+		resp.Diagnostics.Append(data.PushOptions.ElementsAs(ctx, &pushOptions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	var batcher *batcher
+	if data.BatchCommits.ValueBool() {
+		window := defaultBatchWindow
+		if !data.BatchWindow.IsNull() {
+			window, err = time.ParseDuration(data.BatchWindow.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("batch_window"), "Invalid Duration", err.Error())
+				return
+			}
+		}
+		batcher = newBatcher(window)
+	}
+	phaseTimeouts, diags := newPhaseTimeouts(data.CloneTimeout, data.PushTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	retryableErrors, diags := newRetryableErrors(ctx, data.RetryableErrors)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	signer, err := newSigner(data.Signing)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("signing"), "Invalid Signing Key", err.Error())
+		return
+	}
+	verifier, diags := newVerifier(ctx, data.VerifyHead)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if verifier != nil {
+		err = verifier.trusts(signer)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("verify_head"), "Untrusted Signing Key", err.Error())
+			return
+		}
+	}
+	pathPolicy, diags := newPathPolicy(ctx, data.AllowedPaths, data.DeniedPaths)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	protectedBranches, diags := newProtectedBranches(ctx, data.ProtectedBranches)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var auditLog *auditLog
+	if data.AuditLog.ValueString() != "" {
+		auditLog, err = newAuditLog(data.AuditLog.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("audit_log"), "Invalid Audit Log", err.Error())
+			return
+		}
+	}
+	telemetry, diags := newTelemetry(ctx, data.Telemetry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd := &ProviderResourceData{
+		url:               normalizeURL(data.Url.ValueString()),
+		ssh:               data.Ssh,
+		http:              data.Http,
+		credentialHelper:  credentialHelper,
+		httpTransport:     httpTransport,
+		operations:        newSemaphore(data.MaxConcurrentOperations.ValueInt64()),
+		clones:            newSemaphore(data.MaxConcurrentClones.ValueInt64()),
+		readOnly:          data.ReadOnly.ValueString(),
+		azureDevOps:       data.AzureDevOps.ValueBool(),
+		gerrit:            data.Gerrit.ValueBool(),
+		pathPrefix:        strings.Trim(slashPath(data.PathPrefix.ValueString()), "/"),
+		pathPolicy:        pathPolicy,
+		webURLTemplate:    data.WebUrlTemplate.ValueString(),
+		branch:            data.Branch.ValueString(),
+		protectedBranches: protectedBranches,
+		commitDefaults:    newCommitDefaults(data.Commits),
+		signer:            signer,
+		verifier:          verifier,
+		pushOptions:       pushOptions,
+		cloneOptions:      newCloneOptions(data.Clone),
+		workDir:           data.WorkDir.ValueString(),
+		keepTempDirs:      data.KeepTempDirs.ValueBool(),
+		repositoryLocks:   newRepositoryLocks(),
+		refsCache:         newRefsCache(),
+		retryableErrors:   retryableErrors,
+		batcher:           batcher,
+		auditLog:          auditLog,
+		telemetry:         telemetry,
+		phaseTimeouts:     phaseTimeouts,
+	}
+	if data.ValidateConnection.ValueBool() && !data.Url.IsUnknown() {
+		_, err := prd.ListRefs(ctx)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("url"), "Git Connection Error", prd.redact(fmt.Sprintf("Could not list references of %s: %s", prd.url, err)))
+			return
+		}
+	}
+	resp.ResourceData = prd
+	resp.DataSourceData = prd
 }
 
 func (p *GitProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewAttributesEntryResource,
+		NewChangelogEntryResource,
+		NewMailmapEntryResource,
 		NewRepositoryFileResource,
+		NewRepositoryFileAbsentResource,
+		NewRepositoryTemplateResource,
 	}
 }
 
 func (p *GitProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewRepositoryFileDataSource,
+		NewRepositoryFilesDataSource,
+		NewBranchesDataSource,
+		NewRepositoryDiffDataSource,
+		NewRevParseDataSource,
+		NewRefDataSource,
+		NewSshKnownHostsEntryDataSource,
+		NewSemverDataSource,
+		NewRepositoryPathDataSource,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -2,74 +2,792 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"os"
+	"path"
+	"sort"
+	"strings"
 
-	"github.com/fluxcd/flux2/pkg/manifestgen/sourcesecret"
 	"github.com/fluxcd/pkg/git"
-	"github.com/fluxcd/pkg/git/gogit"
-	"github.com/fluxcd/pkg/git/repository"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// defaultBranch is used when the remote repository is empty and no branch is configured.
+const defaultBranch = "main"
+
 type ProviderResourceData struct {
-	url  string
-	ssh  *Ssh
-	http *Http
+	url               string
+	ssh               *Ssh
+	http              *Http
+	credentialHelper  *credentialHelper
+	httpTransport     http.RoundTripper
+	operations        semaphore
+	clones            semaphore
+	readOnly          string
+	azureDevOps       bool
+	gerrit            bool
+	pathPrefix        string
+	pathPolicy        *pathPolicy
+	webURLTemplate    string
+	branch            string
+	protectedBranches []string
+	commitDefaults    commitDefaults
+	signer            *signer
+	verifier          *verifier
+	pushOptions       []string
+	baseBranch        string
+	cloneOptions      cloneOptions
+	workDir           string
+	keepTempDirs      bool
+	repositoryLocks   *repositoryLocks
+	refsCache         *refsCache
+	retryableErrors   map[errorClass]bool
+	batcher           *batcher
+	auditLog          *auditLog
+	telemetry         *telemetry
+	phaseTimeouts     phaseTimeouts
+}
+
+// WithRepository returns resource data which uses the repository configured
+// in the resource instead of the provider repository.
+func (prd *ProviderResourceData) WithRepository(repo *Repository) (*ProviderResourceData, error) {
+	if repo == nil {
+		return prd, nil
+	}
+	httpTransport, err := newHTTPTransport(repo.Http)
+	if err != nil {
+		return nil, err
+	}
+	data := *prd
+	data.url = normalizeURL(repo.Url.ValueString())
+	data.ssh = repo.Ssh
+	data.http = repo.Http
+	data.httpTransport = httpTransport
+	// The provider branch may not exist in another repository.
+	data.branch = ""
+	return &data, nil
+}
+
+// WithPushOptions returns resource data which sends the given push options
+// instead of the provider push options.
+func (prd *ProviderResourceData) WithPushOptions(pushOptions []string) *ProviderResourceData {
+	data := *prd
+	data.pushOptions = pushOptions
+	return &data
+}
+
+// WithBaseBranch returns resource data which creates branches that do not exist
+// in the remote repository from the base branch.
+func (prd *ProviderResourceData) WithBaseBranch(branch string) *ProviderResourceData {
+	data := *prd
+	data.baseBranch = branch
+	return &data
+}
+
+// RepositoryPath returns the path of the file in the repository, rooted under
+// the configured path prefix. Backslashes are converted to forward slashes, as
+// paths may be written with Windows separators.
+func (prd *ProviderResourceData) RepositoryPath(p string) string {
+	p = slashPath(p)
+	if prd.pathPrefix == "" {
+		return p
+	}
+	return path.Join(prd.pathPrefix, p)
+}
+
+// WebURL returns the url where the file can be browsed at the commit, or an
+// empty string when the url is not known for the host of the repository.
+func (prd *ProviderResourceData) WebURL(branch, commit, p string) string {
+	return webURL(prd.webURLTemplate, prd.url, branch, commit, prd.RepositoryPath(p))
+}
+
+// slashPath returns the path with Windows separators replaced by the forward
+// slashes used in git trees.
+func slashPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// GetGitClient returns a working copy of the branch. A temporary clone is made
+// unless a work directory is configured, in which case the cached working copy
+// is updated to the remote branch. The branch is locked until the client is
+// closed, which also removes the temporary clone.
+func (prd *ProviderResourceData) GetGitClient(ctx context.Context, branch string) (*GitClient, error) {
+	u, err := url.Parse(prd.url)
+	if err != nil {
+		return nil, err
+	}
+	authOpts, err := getAuthOpts(ctx, u, prd.http, prd.ssh, prd.credentialHelper)
+	if err != nil {
+		return nil, err
+	}
+	proxyOpts := getProxyOpts(u, prd.ssh)
+
+	release, err := prd.repositoryLocks.Lock(ctx, prd.url, branch)
+	if err != nil {
+		return nil, err
+	}
+	dir := ""
+	temporary := prd.workDir == ""
+	if !temporary {
+		dir = workDirPath(prd.workDir, prd.url, branch)
+	} else {
+		dir, err = os.MkdirTemp("", "terraform-provider-git")
+		if err != nil {
+			release()
+			return nil, err
+		}
+		tflog.Debug(ctx, "Created temporary directory", map[string]interface{}{"path": dir})
+	}
+	checkoutCtx, op := prd.startOperation(ctx, operationClone, branch)
+	checkoutCtx, cancel := withPhaseTimeout(checkoutCtx, prd.phaseTimeouts.clone)
+	repo, err := prd.checkout(checkoutCtx, dir, branch, authOpts, proxyOpts)
+	err = phaseError(ctx, checkoutCtx, operationClone, prd.phaseTimeouts.clone, err)
+	cancel()
+	prd.endOperation(ctx, op, nil, "", err)
+	if err != nil {
+		if temporary && !prd.keepTempDirs {
+			os.RemoveAll(dir)
+		}
+		release()
+		return nil, err
+	}
+	return &GitClient{
+		path:             dir,
+		removePath:       temporary && !prd.keepTempDirs,
+		bare:             prd.cloneOptions.noCheckout,
+		removals:         map[string]struct{}{},
+		modes:            map[string]filemode.FileMode{},
+		repo:             repo,
+		release:          release,
+		url:              u,
+		authOpts:         authOpts,
+		credentialHelper: prd.credentialHelper,
+		proxy:            proxyOpts,
+		httpTransport:    prd.httpTransport,
+		operations:       prd.operations,
+		readOnly:         prd.readOnly,
+		gerrit:           prd.gerrit,
+		signer:           prd.signer,
+		pushOptions:      prd.pushOptions,
+	}, nil
+}
+
+// ReadFile returns the content of the file and the head commit of the branch.
+func (prd *ProviderResourceData) ReadFile(ctx context.Context, branch, p string) ([]byte, string, error) {
+	var b []byte
+	hash, _, err := prd.StreamFile(ctx, branch, p, func(r io.Reader) error {
+		var err error
+		b, err = io.ReadAll(r)
+		return err
+	})
+	return b, hash, err
+}
+
+// StreamFile calls fn with the content of the file and returns the head commit
+// of the branch and the file, which contains the hash of the blob and the mode
+// of the file. The content is not read when fn is nil. Only the latest commit
+// of the branch is fetched into memory unless a work directory is configured,
+// in which case the file is read from the cached working copy.
+func (prd *ProviderResourceData) StreamFile(ctx context.Context, branch, p string, fn func(r io.Reader) error) (string, *object.File, error) {
+	hash := ""
+	var file *object.File
+	err := prd.withHeadRepository(ctx, branch, p, func(repo *extgogit.Repository) error {
+		var err error
+		hash, file, err = streamHeadFile(repo, p, fn)
+		return err
+	})
+	return hash, file, err
+}
+
+// withHeadRepository calls fn with a repository containing the latest commit of
+// the branch.
+func (prd *ProviderResourceData) withHeadRepository(ctx context.Context, branch, p string, fn func(repo *extgogit.Repository) error) error {
+	if prd.workDir != "" {
+		client, err := prd.GetGitClient(ctx, branch)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		return fn(client.repo)
+	}
+
+	repo, err := prd.fetchBranch(ctx, branch, 1)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return &os.PathError{Op: "read", Path: p, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return err
+	}
+	return fn(repo)
+}
+
+// StreamFileAtCommit calls fn with the content of the file at the commit and
+// returns the commit and the file. Only the commit is fetched when the server
+// allows fetching commits which are not advertised, otherwise the history of
+// all branches and tags is fetched.
+func (prd *ProviderResourceData) StreamFileAtCommit(ctx context.Context, commit, p string, fn func(r io.Reader) error) (*object.Commit, *object.File, error) {
+	repo, err := prd.fetchCommits(ctx, plumbing.NewHash(commit))
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, nil, fmt.Errorf("commit %s does not exist in the repository", commit)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := streamCommitFile(repo, c.Hash, p, fn)
+	return c, file, err
+}
+
+// LastCommit returns the most recent commit of the branch which changed the
+// file, following the first parent of merge commits. Only the given number of
+// commits are fetched into memory, and nil is returned when the file has not
+// been changed within them.
+func (prd *ProviderResourceData) LastCommit(ctx context.Context, branch, p string, depth int) (*object.Commit, error) {
+	repo, err := prd.fetchBranch(ctx, branch, depth)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, &os.PathError{Op: "read", Path: p, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return nil, err
+	}
+	commit, err := headCommit(repo)
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return nil, &os.PathError{Op: "read", Path: p, Err: os.ErrNotExist}
+	}
+	file, err := commit.File(treePath(p))
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, &os.PathError{Op: "read", Path: p, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for commit.NumParents() > 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		parent, err := commit.Parent(0)
+		// The history ends at the depth, before the commit which changed the file.
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		parentFile, err := parent.File(treePath(p))
+		if errors.Is(err, object.ErrFileNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parentFile.Hash != file.Hash || parentFile.Mode != file.Mode {
+			break
+		}
+		commit = parent
+	}
+	return commit, nil
+}
+
+// DiffPath returns the files which differ between the base and head commits,
+// either in content or in mode. The path is either a file or a directory, and
+// the whole repository is compared when the path is empty.
+func (prd *ProviderResourceData) DiffPath(ctx context.Context, base, head, p string) ([]string, error) {
+	repo, err := prd.fetchCommits(ctx, plumbing.NewHash(base), plumbing.NewHash(head))
+	if err != nil {
+		return nil, err
+	}
+	baseFiles, err := commitFiles(repo, base, p)
+	if err != nil {
+		return nil, err
+	}
+	headFiles, err := commitFiles(repo, head, p)
+	if err != nil {
+		return nil, err
+	}
+	changed := []string{}
+	for name, entry := range baseFiles {
+		if headEntry, ok := headFiles[name]; !ok || headEntry != entry {
+			changed = append(changed, name)
+		}
+	}
+	for name := range headFiles {
+		if _, ok := baseFiles[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// ListFiles returns the head commit of the branch and the sorted files at or
+// below the path in its tree. There are no files in an empty repository.
+func (prd *ProviderResourceData) ListFiles(ctx context.Context, branch, p string) (string, []string, error) {
+	hash := ""
+	files := []string{}
+	err := prd.withHeadRepository(ctx, branch, p, func(repo *extgogit.Repository) error {
+		head, err := repo.Head()
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		hash = head.Hash().String()
+		entries, err := commitFiles(repo, hash, p)
+		if err != nil {
+			return err
+		}
+		for name := range entries {
+			files = append(files, name)
+		}
+		sort.Strings(files)
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return "", files, nil
+	}
+	return hash, files, err
+}
+
+type fileEntry struct {
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+// commitFiles returns the files at or below the path in the tree of the
+// commit, which is empty when the path does not exist.
+func commitFiles(repo *extgogit.Repository, hash, p string) (map[string]fileEntry, error) {
+	commit, err := repo.CommitObject(plumbing.NewHash(hash))
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, fmt.Errorf("commit %s does not exist in the repository", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]fileEntry{}
+	prefix := ""
+	if p != "" {
+		entry, err := tree.FindEntry(treePath(p))
+		if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry.Mode != filemode.Dir {
+			files[treePath(p)] = fileEntry{hash: entry.Hash, mode: entry.Mode}
+			return files, nil
+		}
+		tree, err = tree.Tree(treePath(p))
+		if err != nil {
+			return nil, err
+		}
+		prefix = treePath(p) + "/"
+	}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files[prefix+f.Name] = fileEntry{hash: f.Hash, mode: f.Mode}
+		return nil
+	})
+	return files, err
+}
+
+// streamHeadFile calls fn with the content of the file in the tree of HEAD and
+// returns the head commit and the file.
+func streamHeadFile(repo *extgogit.Repository, p string, fn func(r io.Reader) error) (string, *object.File, error) {
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", nil, &os.PathError{Op: "read", Path: p, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	file, err := streamCommitFile(repo, head.Hash(), p, fn)
+	return head.Hash().String(), file, err
+}
+
+// streamCommitFile calls fn with the content of the file in the tree of the
+// commit and returns the file. The content is not read when fn is nil.
+func streamCommitFile(repo *extgogit.Repository, hash plumbing.Hash, p string, fn func(r io.Reader) error) (*object.File, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	file, err := commit.File(treePath(p))
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, &os.PathError{Op: "read", Path: p, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return file, nil
+	}
+	r, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return file, fn(r)
+}
+
+// RemoteHead returns the commit which the branch points to in the remote
+// repository, or an empty string when the branch does not exist.
+func (prd *ProviderResourceData) RemoteHead(ctx context.Context, branch string) (string, error) {
+	refs, err := prd.ListRefs(ctx)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.Name() == plumbing.NewBranchReferenceName(branch) {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", nil
+}
+
+// ResolveCommit returns the commit which the ref points to in the remote
+// repository. The ref is either the full SHA of a commit, a tag or a branch,
+// and the head of the branch is returned when the ref is empty.
+func (prd *ProviderResourceData) ResolveCommit(ctx context.Context, branch, ref string) (string, error) {
+	if ref == "" {
+		hash, err := prd.RemoteHead(ctx, branch)
+		if err != nil {
+			return "", err
+		}
+		if hash == "" {
+			return "", fmt.Errorf("branch %s does not exist in the repository", branch)
+		}
+		return hash, nil
+	}
+	if commitRegex.MatchString(ref) {
+		return ref, nil
+	}
+	refs, err := prd.ListRefs(ctx)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return "", err
+	}
+	names := []plumbing.ReferenceName{
+		plumbing.NewTagReferenceName(ref),
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.ReferenceName(ref),
+	}
+	for _, name := range names {
+		hash := ""
+		for _, r := range refs {
+			// Annotated tags are advertised together with the peeled commit.
+			if r.Name().String() == name.String()+"^{}" {
+				return r.Hash().String(), nil
+			}
+			if r.Name() == name {
+				hash = r.Hash().String()
+			}
+		}
+		if hash != "" {
+			return hash, nil
+		}
+	}
+	return "", fmt.Errorf("ref %s does not exist in the repository", ref)
+}
+
+// fetchBranch fetches the commits of the branch into memory, without checking
+// out a worktree. Only the given number of commits are fetched, or the whole
+// history of the branch when the depth is zero.
+func (prd *ProviderResourceData) fetchBranch(ctx context.Context, branch string, depth int) (*extgogit.Repository, error) {
+	u, err := url.Parse(prd.url)
+	if err != nil {
+		return nil, err
+	}
+	authOpts, err := getAuthOpts(ctx, u, prd.http, prd.ssh, prd.credentialHelper)
+	if err != nil {
+		return nil, err
+	}
+	err = prd.checkCredentialsOverHTTP(u, authOpts)
+	if err != nil {
+		return nil, err
+	}
+	authMethod, err := transportAuth(authOpts)
+	if err != nil {
+		return nil, err
+	}
+	release, err := prd.acquireClone(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	cloneCtx, op := prd.startOperation(withHTTPTransport(ctx, prd.httpTransport), operationClone, branch)
+	cloneCtx, cancel := withPhaseTimeout(cloneCtx, prd.phaseTimeouts.clone)
+	defer cancel()
+	repo, err := extgogit.CloneContext(cloneCtx, memory.NewStorage(), nil, &extgogit.CloneOptions{
+		URL:           prd.url,
+		Auth:          authMethod,
+		RemoteName:    extgogit.DefaultRemoteName,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		SingleBranch:  true,
+		Depth:         depth,
+		Tags:          extgogit.NoTags,
+		ProxyOptions:  getProxyOpts(u, prd.ssh),
+	})
+	err = phaseError(ctx, cloneCtx, operationClone, prd.phaseTimeouts.clone, err)
+	prd.endOperation(ctx, op, nil, "", err)
+	return repo, err
 }
 
-func (prd *ProviderResourceData) GetGitClient(ctx context.Context, branch string) (*gogit.Client, error) {
+// fetchCommits fetches the commits into memory, without checking out a
+// worktree.
+func (prd *ProviderResourceData) fetchCommits(ctx context.Context, hashes ...plumbing.Hash) (*extgogit.Repository, error) {
 	u, err := url.Parse(prd.url)
 	if err != nil {
 		return nil, err
 	}
-	authOpts, err := getAuthOpts(u, prd.http, prd.ssh)
+	authOpts, err := getAuthOpts(ctx, u, prd.http, prd.ssh, prd.credentialHelper)
 	if err != nil {
 		return nil, err
 	}
-	clientOpts := []gogit.ClientOption{gogit.WithDiskStorage()}
-	if prd.http != nil && prd.http.InsecureHttpAllowed.ValueBool() {
-		clientOpts = append(clientOpts, gogit.WithInsecureCredentialsOverHTTP())
+	err = prd.checkCredentialsOverHTTP(u, authOpts)
+	if err != nil {
+		return nil, err
 	}
-	tmpDir, err := os.MkdirTemp("", "terraform-provider-git")
+	authMethod, err := transportAuth(authOpts)
 	if err != nil {
 		return nil, err
 	}
-	client, err := gogit.NewClient(tmpDir, authOpts, clientOpts...)
+	repo, err := extgogit.Init(memory.NewStorage(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("could not create git client: %w", err)
+		return nil, err
 	}
-	_, err = client.Clone(ctx, prd.url, repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Branch: branch}})
+	remote, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: extgogit.DefaultRemoteName,
+		URLs: []string{prd.url},
+	})
 	if err != nil {
 		return nil, err
 	}
-	return client, nil
+	release, err := prd.acquireClone(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	ctx = withHTTPTransport(ctx, prd.httpTransport)
+	refSpecs := []config.RefSpec{}
+	commits := []string{}
+	for i, hash := range hashes {
+		refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("%s:refs/pinned/%d", hash, i)))
+		commits = append(commits, hash.String())
+	}
+	opts := &extgogit.FetchOptions{
+		RefSpecs:     refSpecs,
+		Depth:        1,
+		Auth:         authMethod,
+		Tags:         extgogit.NoTags,
+		ProxyOptions: getProxyOpts(u, prd.ssh),
+	}
+	fetchCtx, cancel := withPhaseTimeout(ctx, prd.phaseTimeouts.clone)
+	defer cancel()
+	err = remote.FetchContext(fetchCtx, opts)
+	if errors.Is(err, extgogit.ErrExactSHA1NotSupported) {
+		tflog.Debug(ctx, "Fetching all branches and tags as the server does not allow fetching a commit", map[string]interface{}{"commits": commits})
+		opts.RefSpecs = []config.RefSpec{
+			config.RefSpec("+refs/heads/*:refs/remotes/origin/*"),
+			config.RefSpec("+refs/tags/*:refs/tags/*"),
+		}
+		opts.Depth = 0
+		err = remote.FetchContext(fetchCtx, opts)
+	}
+	err = phaseError(ctx, fetchCtx, operationClone, prd.phaseTimeouts.clone, err)
+	if err != nil && !errors.Is(err, extgogit.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("unable to fetch commit %s: %w", strings.Join(commits, ", "), err)
+	}
+	return repo, nil
+}
+
+// checkout makes the directory contain the remote branch, updating an existing
+// working copy in the directory or cloning the repository.
+func (prd *ProviderResourceData) checkout(ctx context.Context, dir, branch string, authOpts *git.AuthOptions, proxyOpts transport.ProxyOptions) (*extgogit.Repository, error) {
+	release, err := prd.acquireClone(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	ctx = withHTTPTransport(ctx, prd.httpTransport)
+
+	if prd.workDir != "" {
+		repo, err := extgogit.PlainOpen(dir)
+		if err == nil && isBare(repo) != prd.cloneOptions.noCheckout {
+			err = fmt.Errorf("working copy does not match the no_checkout clone option")
+		}
+		if err == nil {
+			err = prd.update(ctx, repo, branch, authOpts, proxyOpts)
+			if err == nil {
+				return repo, nil
+			}
+			tflog.Warn(ctx, "Cloning the repository as the cached working copy could not be updated", map[string]interface{}{"path": dir, "error": prd.redact(err.Error())})
+		}
+		err = os.RemoveAll(dir)
+		if err != nil {
+			return nil, err
+		}
+		err = os.MkdirAll(dir, 0o700)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return prd.clone(ctx, dir, branch, authOpts, proxyOpts)
+}
+
+// ListRefs returns the references advertised by the remote repository
+// without cloning it. The references are cached until the next push to the
+// repository.
+func (prd *ProviderResourceData) ListRefs(ctx context.Context) ([]*plumbing.Reference, error) {
+	return prd.refsCache.Get(ctx, prd.url, func() ([]*plumbing.Reference, error) {
+		return prd.listRefs(ctx)
+	})
 }
 
-func getAuthOpts(u *url.URL, h *Http, s *Ssh) (*git.AuthOptions, error) {
+func (prd *ProviderResourceData) listRefs(ctx context.Context) ([]*plumbing.Reference, error) {
+	u, err := url.Parse(prd.url)
+	if err != nil {
+		return nil, err
+	}
+	authOpts, err := getAuthOpts(ctx, u, prd.http, prd.ssh, prd.credentialHelper)
+	if err != nil {
+		return nil, err
+	}
+	err = prd.checkCredentialsOverHTTP(u, authOpts)
+	if err != nil {
+		return nil, err
+	}
+	authMethod, err := transportAuth(authOpts)
+	if err != nil {
+		return nil, err
+	}
+	remote := extgogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: extgogit.DefaultRemoteName,
+		URLs: []string{prd.url},
+	})
+	ctx = withHTTPTransport(ctx, prd.httpTransport)
+	return remote.ListContext(ctx, &extgogit.ListOptions{
+		Auth:          authMethod,
+		ProxyOptions:  getProxyOpts(u, prd.ssh),
+		PeelingOption: extgogit.AppendPeeled,
+	})
+}
+
+// ResolveBranch returns the branch configured in the resource, falling back to
+// the provider branch and then to the default branch of the remote repository.
+func (prd *ProviderResourceData) ResolveBranch(ctx context.Context, branch types.String) (string, error) {
+	if branch.ValueString() != "" {
+		return branch.ValueString(), nil
+	}
+	if prd.branch != "" {
+		return prd.branch, nil
+	}
+	return prd.RemoteDefaultBranch(ctx)
+}
+
+// RemoteDefaultBranch returns the default branch of the remote repository.
+func (prd *ProviderResourceData) RemoteDefaultBranch(ctx context.Context) (string, error) {
+	refs, err := prd.ListRefs(ctx)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return defaultBranch, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return remoteDefaultBranch(refs)
+}
+
+// remoteDefaultBranch returns the branch which the remote HEAD points to.
+func remoteDefaultBranch(refs []*plumbing.Reference) (string, error) {
+	var head *plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+			break
+		}
+	}
+	if head == nil {
+		return "", fmt.Errorf("could not determine default branch as the remote does not advertise HEAD, set the branch explicitly")
+	}
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target().Short(), nil
+	}
+	// Servers without the symref capability only advertise the HEAD commit.
+	for _, ref := range refs {
+		if ref.Name().IsBranch() && ref.Hash() == head.Hash() {
+			return ref.Name().Short(), nil
+		}
+	}
+	return "", fmt.Errorf("could not determine default branch from remote HEAD %s, set the branch explicitly", head.Hash())
+}
+
+func getProxyOpts(u *url.URL, s *Ssh) transport.ProxyOptions {
+	if u.Scheme != "ssh" || s == nil {
+		return transport.ProxyOptions{}
+	}
+	return transport.ProxyOptions{URL: s.ProxyUrl.ValueString()}
+}
+
+func getAuthOpts(ctx context.Context, u *url.URL, h *Http, s *Ssh, ch *credentialHelper) (*git.AuthOptions, error) {
 	switch u.Scheme {
-	case "http":
+	case "http", "https":
+		// Credentials are optional to allow anonymous access to public repositories.
+		authOpts := &git.AuthOptions{
+			Transport: git.TransportType(u.Scheme),
+		}
+		if h != nil {
+			authOpts.Username = h.Username.ValueString()
+			authOpts.Password = h.Password.ValueString()
+		}
+		err := ch.Fill(ctx, u, authOpts)
+		if err != nil {
+			return nil, err
+		}
+		return authOpts, nil
+	case "git":
+		// The git protocol does not support authentication. The Flux client lacks a
+		// transport type for it, but HTTP without credentials results in no auth method.
 		return &git.AuthOptions{
 			Transport: git.HTTP,
-			Username:  h.Username.ValueString(),
-			Password:  h.Password.ValueString(),
-		}, nil
-	case "https":
-		return &git.AuthOptions{
-			Transport: git.HTTPS,
-			Username:  h.Username.ValueString(),
-			Password:  h.Password.ValueString(),
-			CAFile:    []byte(h.CertificateAuthority.ValueString()),
 		}, nil
 	case "ssh":
-		if s.PrivateKey.ValueString() != "" {
-			kh, err := sourcesecret.ScanHostKey(u.Host)
+		if s != nil && s.PrivateKey.ValueString() != "" {
+			kh, err := knownHosts(u, s)
 			if err != nil {
 				return nil, err
 			}
+			username := s.Username.ValueString()
+			if username == "" {
+				username = u.User.Username()
+			}
 			return &git.AuthOptions{
 				Transport:  git.SSH,
-				Username:   s.Username.ValueString(),
+				Username:   username,
 				Password:   s.Password.ValueString(),
 				Identity:   []byte(s.PrivateKey.ValueString()),
 				KnownHosts: kh,
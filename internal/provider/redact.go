@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// redactedValue replaces credentials in logs and diagnostics. It is the same
+// placeholder as used by url.URL.Redacted.
+const redactedValue = "xxxxx"
+
+var urlUserinfoRegex = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*)://([^/?#@\s'"]*)@`)
+
+// redactURLCredentials masks the credentials of urls in the string. Passwords
+// are always masked, and usernames are masked for http urls as access tokens
+// are commonly used as the username.
+func redactURLCredentials(s string) string {
+	return urlUserinfoRegex.ReplaceAllStringFunc(s, func(m string) string {
+		sub := urlUserinfoRegex.FindStringSubmatch(m)
+		scheme, userinfo := sub[1], sub[2]
+		username, _, hasPassword := strings.Cut(userinfo, ":")
+		switch {
+		case hasPassword:
+			userinfo = username + ":" + redactedValue
+		case strings.HasPrefix(strings.ToLower(scheme), "http"):
+			userinfo = redactedValue
+		}
+		return scheme + "://" + userinfo + "@"
+	})
+}
+
+// redact masks credentials in the string, which may be an error message from
+// the transport layer. Credentials in urls are masked, together with the
+// passwords, tokens and headers configured for the repository or returned by
+// the credential helper.
+func (prd *ProviderResourceData) redact(s string) string {
+	for _, secret := range prd.secrets() {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return redactURLCredentials(s)
+}
+
+// secrets returns the non-empty credentials known for the repository.
+func (prd *ProviderResourceData) secrets() []string {
+	secrets := []string{}
+	if u, err := url.Parse(prd.url); err == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			secrets = append(secrets, password)
+		} else if strings.HasPrefix(u.Scheme, "http") {
+			secrets = append(secrets, u.User.Username())
+		}
+	}
+	// The http configuration is the one of the repository block when a resource
+	// overrides the repository.
+	if prd.http != nil {
+		secrets = append(secrets, prd.http.Password.ValueString())
+		for name, v := range prd.http.Headers.Elements() {
+			if header, ok := v.(types.String); ok && credentialHeader(name) {
+				secrets = append(secrets, headerCredentials(header.ValueString())...)
+			}
+		}
+	}
+	if prd.ssh != nil {
+		secrets = append(secrets, prd.ssh.Password.ValueString())
+	}
+	secrets = append(secrets, prd.credentialHelper.Secrets()...)
+
+	nonEmpty := []string{}
+	for _, secret := range secrets {
+		if secret != "" {
+			nonEmpty = append(nonEmpty, secret)
+		}
+	}
+	return nonEmpty
+}
+
+// credentialHeader returns true for headers which carry credentials, such as
+// the token of a proxy. The values of other headers are not redacted, as short
+// values such as a request id would otherwise be masked wherever they appear.
+func credentialHeader(name string) bool {
+	name = strings.ToLower(name)
+	return name == "authorization" || name == "proxy-authorization" || strings.HasSuffix(name, "-token")
+}
+
+// headerCredentials returns the value of the header, together with the
+// credentials following the authentication scheme, for example the token of a
+// bearer authorization.
+func headerCredentials(value string) []string {
+	credentials := []string{value}
+	if _, credential, ok := strings.Cut(value, " "); ok && strings.TrimSpace(credential) != "" {
+		credentials = append(credentials, strings.TrimSpace(credential))
+	}
+	return credentials
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RefDataSourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	Branch types.String `tfsdk:"branch"`
+	Tag    types.String `tfsdk:"tag"`
+	Exists types.Bool   `tfsdk:"exists"`
+	SHA    types.String `tfsdk:"sha"`
+}
+
+var _ datasource.DataSource = &RefDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &RefDataSource{}
+
+func NewRefDataSource() datasource.DataSource {
+	return &RefDataSource{}
+}
+
+type RefDataSource struct {
+	prd *ProviderResourceData
+}
+
+func (d *RefDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ref"
+}
+
+func (d *RefDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Ref data source, which reports whether a branch or tag exists in the remote repository without failing when it does not.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Name of the branch. Exactly one of `branch` and `tag` has to be set.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"tag": schema.StringAttribute{
+				Description: "Name of the tag. Exactly one of `branch` and `tag` has to be set.",
+				Optional:    true,
+			},
+			"exists": schema.BoolAttribute{
+				Description: "Whether the branch or tag exists.",
+				Computed:    true,
+			},
+			"sha": schema.StringAttribute{
+				Description: "SHA of the commit which the branch or tag points to, or null when it does not exist. Annotated tags are resolved to the tagged commit.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *RefDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.prd = prd
+}
+
+func (d *RefDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data RefDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Branch.IsUnknown() || data.Tag.IsUnknown() {
+		return
+	}
+	if data.Branch.IsNull() == data.Tag.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Invalid Attribute Combination", "Exactly one of branch and tag has to be set.")
+	}
+}
+
+func (d *RefDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *RefDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	name := plumbing.NewBranchReferenceName(data.Branch.ValueString())
+	if !data.Tag.IsNull() {
+		name = plumbing.NewTagReferenceName(data.Tag.ValueString())
+	}
+	refs, err := d.prd.ListRefs(ctx)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		d.prd.addGitError(&resp.Diagnostics, "Git Ref Error", err)
+		return
+	}
+	data.SHA = types.StringNull()
+	for _, ref := range refs {
+		// Annotated tags are advertised together with the peeled commit.
+		if ref.Name().String() == name.String()+"^{}" {
+			data.SHA = types.StringValue(ref.Hash().String())
+			break
+		}
+		if ref.Name() == name {
+			data.SHA = types.StringValue(ref.Hash().String())
+		}
+	}
+	data.Exists = types.BoolValue(!data.SHA.IsNull())
+	data.ID = types.StringValue(name.String())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
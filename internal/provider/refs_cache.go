@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// refsCache caches the references advertised by remote repositories, so that
+// the remote is only contacted once when many resources read the references
+// of the same repository. Concurrent requests for the same repository wait for
+// the first request. A nil cache does not cache anything.
+type refsCache struct {
+	mu      sync.Mutex
+	entries map[string]*refsEntry
+}
+
+type refsEntry struct {
+	done chan struct{}
+	refs []*plumbing.Reference
+	err  error
+}
+
+func newRefsCache() *refsCache {
+	return &refsCache{
+		entries: map[string]*refsEntry{},
+	}
+}
+
+// Get returns the cached references of the repository, calling list when the
+// references are not cached. Errors are not cached, except for the error
+// returned for empty repositories.
+func (c *refsCache) Get(ctx context.Context, repoURL string, list func() ([]*plumbing.Reference, error)) ([]*plumbing.Reference, error) {
+	if c == nil {
+		return list()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[repoURL]
+	if ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.refs, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	entry = &refsEntry{done: make(chan struct{})}
+	c.entries[repoURL] = entry
+	c.mu.Unlock()
+
+	entry.refs, entry.err = list()
+	if entry.err != nil && !errors.Is(entry.err, transport.ErrEmptyRemoteRepository) {
+		c.Invalidate(repoURL)
+	}
+	close(entry.done)
+	return entry.refs, entry.err
+}
+
+// Invalidate removes the cached references of the repository, which have to
+// be listed again after pushing.
+func (c *refsCache) Invalidate(repoURL string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, repoURL)
+	c.mu.Unlock()
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Repository overrides the repository and credentials configured in the provider.
+type Repository struct {
+	Url  types.String `tfsdk:"url"`
+	Ssh  *Ssh         `tfsdk:"ssh"`
+	Http *Http        `tfsdk:"http"`
+}
+
+// validateRepository returns errors for url schemes which are not supported,
+// and for ssh and http blocks which do not match the scheme of the url. Paths
+// are relative to the parent path of the url attribute.
+func validateRepository(parent path.Path, repoURL types.String, s *Ssh, h *Http) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if repoURL.IsNull() || repoURL.IsUnknown() {
+		return diags
+	}
+	u, err := url.Parse(normalizeURL(repoURL.ValueString()))
+	if err != nil {
+		// The error contains the url, which may not be redacted as a whole when
+		// it cannot be parsed.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		diags.AddAttributeError(parent.AtName("url"), "Invalid URL", fmt.Sprintf("Url could not be parsed: %s", redactURLCredentials(err.Error())))
+		return diags
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git":
+	default:
+		diags.AddAttributeError(parent.AtName("url"), "Invalid URL Scheme", fmt.Sprintf("Scheme %q is not supported, use https, http, ssh or git.", u.Scheme))
+		return diags
+	}
+
+	if u.Scheme == "ssh" {
+		if s == nil {
+			diags.AddAttributeError(parent.AtName("ssh"), "Missing SSH Configuration", "The ssh block with a private key is required for ssh urls.")
+		} else if s.PrivateKey.IsNull() {
+			diags.AddAttributeError(parent.AtName("ssh").AtName("private_key"), "Missing SSH Private Key", "A private key is required for ssh urls.")
+		} else if s.OfflineKnownHosts.ValueBool() && s.KnownHosts.IsNull() {
+			diags.AddAttributeError(parent.AtName("ssh").AtName("known_hosts"), "Missing SSH Known Hosts", "Known hosts are required when offline_known_hosts is set.")
+		}
+	} else if s != nil {
+		diags.AddAttributeError(parent.AtName("ssh"), "Invalid SSH Configuration", fmt.Sprintf("The ssh block cannot be used with %s urls.", u.Scheme))
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		if h != nil {
+			diags.AddAttributeError(parent.AtName("http"), "Invalid HTTP Configuration", fmt.Sprintf("The http block cannot be used with %s urls.", u.Scheme))
+		}
+		return diags
+	}
+	hasCredentials := h != nil && (!h.Username.IsNull() || !h.Password.IsNull())
+	insecureAllowed := h != nil && (h.InsecureHttpAllowed.IsUnknown() || h.InsecureHttpAllowed.ValueBool())
+	if u.Scheme == "http" && hasCredentials && !insecureAllowed {
+		diags.AddAttributeError(parent.AtName("http").AtName("allow_insecure_http"), "Insecure HTTP Credentials", "Credentials can only be sent over http urls when allow_insecure_http is set.")
+	}
+	return diags
+}
+
+func repositoryResourceAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Overrides the repository url and credentials configured in the provider.",
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				Description: "Url of the Git repository using the `https`, `http`, `ssh` or anonymous `git` protocol. Scp-style urls such as `git@github.com:org/repo.git` are supported for SSH.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ssh":  resourceConnectionAttribute(sshAttributes()),
+			"http": resourceConnectionAttribute(httpAttributes()),
+		},
+		Optional: true,
+	}
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RepositoryDiffDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Path         types.String `tfsdk:"path"`
+	Base         types.String `tfsdk:"base"`
+	Head         types.String `tfsdk:"head"`
+	BaseCommit   types.String `tfsdk:"base_commit"`
+	HeadCommit   types.String `tfsdk:"head_commit"`
+	Differs      types.Bool   `tfsdk:"differs"`
+	ChangedFiles types.List   `tfsdk:"changed_files"`
+}
+
+var _ datasource.DataSource = &RepositoryDiffDataSource{}
+
+func NewRepositoryDiffDataSource() datasource.DataSource {
+	return &RepositoryDiffDataSource{}
+}
+
+type RepositoryDiffDataSource struct {
+	prd *ProviderResourceData
+}
+
+func (d *RepositoryDiffDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repository_diff"
+}
+
+func (d *RepositoryDiffDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Repository diff data source",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the file or directory which is compared. Defaults to the whole repository, or the provider path prefix.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"base": schema.StringAttribute{
+				Description: "Branch, tag or full commit SHA which is compared, for example `prod`.",
+				Required:    true,
+			},
+			"head": schema.StringAttribute{
+				Description: "Branch, tag or full commit SHA which the base is compared with, for example `staging`.",
+				Required:    true,
+			},
+			"base_commit": schema.StringAttribute{
+				Description: "SHA of the commit of the base.",
+				Computed:    true,
+			},
+			"head_commit": schema.StringAttribute{
+				Description: "SHA of the commit of the head.",
+				Computed:    true,
+			},
+			"differs": schema.BoolAttribute{
+				Description: "Whether any file differs between the base and the head.",
+				Computed:    true,
+			},
+			"changed_files": schema.ListAttribute{
+				Description: "Files which were added, removed or modified between the base and the head, relative to the provider path prefix.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *RepositoryDiffDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.prd = prd
+}
+
+func (d *RepositoryDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *RepositoryDiffDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	base, err := d.prd.ResolveCommit(ctx, "", data.Base.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("base"), "Git Ref Error", d.prd.redact(err.Error()))
+		return
+	}
+	head, err := d.prd.ResolveCommit(ctx, "", data.Head.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("head"), "Git Ref Error", d.prd.redact(err.Error()))
+		return
+	}
+	changed, err := d.prd.DiffPath(ctx, base, head, d.prd.RepositoryPath(data.Path.ValueString()))
+	if err != nil {
+		d.prd.addGitError(&resp.Diagnostics, "Git Diff Error", err)
+		return
+	}
+	if d.prd.pathPrefix != "" {
+		for i, name := range changed {
+			changed[i] = strings.TrimPrefix(name, d.prd.pathPrefix+"/")
+		}
+	}
+	changedFiles, diags := types.ListValueFrom(ctx, types.StringType, changed)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s...%s:%s", base, head, data.Path.ValueString()))
+	data.BaseCommit = types.StringValue(base)
+	data.HeadCommit = types.StringValue(head)
+	data.Differs = types.BoolValue(len(changed) > 0)
+	data.ChangedFiles = changedFiles
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
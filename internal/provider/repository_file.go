@@ -2,17 +2,25 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/fluxcd/pkg/git"
 	"github.com/fluxcd/pkg/git/repository"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -20,25 +28,94 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
 )
 
 type RepositoryFileResourceModel struct {
-	ID               types.String   `tfsdk:"id"`
-	Branch           types.String   `tfsdk:"branch"`
-	Path             types.String   `tfsdk:"path"`
-	Content          types.String   `tfsdk:"content"`
-	OverrideOnCreate types.Bool     `tfsdk:"override_on_create"`
-	AuthorName       types.String   `tfsdk:"author_name"`
-	AuthorEmail      types.String   `tfsdk:"author_email"`
-	Message          types.String   `tfsdk:"message"`
-	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+	ID                    types.String   `tfsdk:"id"`
+	Repository            *Repository    `tfsdk:"repository"`
+	Branch                types.String   `tfsdk:"branch"`
+	Path                  types.String   `tfsdk:"path"`
+	Content               types.String   `tfsdk:"content"`
+	ContentFile           types.String   `tfsdk:"content_file"`
+	SourceRef             types.String   `tfsdk:"source_ref"`
+	SourcePath            types.String   `tfsdk:"source_path"`
+	SourceCommit          types.String   `tfsdk:"source_commit"`
+	SensitiveContent      types.String   `tfsdk:"sensitive_content"`
+	ContentBase64         types.String   `tfsdk:"content_base64"`
+	ContentSHA256         types.String   `tfsdk:"content_sha256"`
+	CommitSHA             types.String   `tfsdk:"commit_sha"`
+	WebURL                types.String   `tfsdk:"web_url"`
+	LastCommitSHA         types.String   `tfsdk:"last_commit_sha"`
+	LastAuthor            types.String   `tfsdk:"last_author"`
+	LastModified          types.String   `tfsdk:"last_modified"`
+	LastCommitDepth       types.Int64    `tfsdk:"last_commit_depth"`
+	OverrideOnCreate      types.Bool     `tfsdk:"override_on_create"`
+	ErrorIfMissing        types.Bool     `tfsdk:"error_if_missing"`
+	DriftDetection        types.String   `tfsdk:"drift_detection"`
+	DriftComparison       types.String   `tfsdk:"drift_comparison"`
+	Newline               types.String   `tfsdk:"newline"`
+	EnsureTrailingNewline types.Bool     `tfsdk:"ensure_trailing_newline"`
+	BOM                   types.String   `tfsdk:"bom"`
+	Executable            types.Bool     `tfsdk:"executable"`
+	DestroyStrategy       types.String   `tfsdk:"destroy_strategy"`
+	CreateBranchIfMissing types.Bool     `tfsdk:"create_branch_if_missing"`
+	BaseBranch            types.String   `tfsdk:"base_branch"`
+	OnRemoteChange        types.String   `tfsdk:"on_remote_change"`
+	AuthorName            types.String   `tfsdk:"author_name"`
+	AuthorEmail           types.String   `tfsdk:"author_email"`
+	Message               types.String   `tfsdk:"message"`
+	CoAuthors             types.List     `tfsdk:"co_authors"`
+	PushOptions           types.List     `tfsdk:"push_options"`
+	Refspecs              types.List     `tfsdk:"refspecs"`
+	MergeRequest          *MergeRequest  `tfsdk:"merge_request"`
+	Timeouts              timeouts.Value `tfsdk:"timeouts"`
 }
 
+const (
+	driftDetectionNone = "none"
+	driftDetectionFull = "full"
+)
+
+const (
+	driftComparisonExact            = "exact"
+	driftComparisonIgnoreWhitespace = "ignore_whitespace"
+)
+
+const (
+	bomPreserve = "preserve"
+	bomStrip    = "strip"
+	bomAdd      = "add"
+)
+
+// utf8BOM is the byte order mark of UTF-8 encoded text.
+const utf8BOM = "\ufeff"
+
+const (
+	destroyStrategyDelete = "delete"
+	destroyStrategyKeep   = "keep"
+)
+
+const (
+	remoteChangeOverwrite = "overwrite"
+	remoteChangeWarn      = "warn"
+	remoteChangeFail      = "fail"
+)
+
+const (
+	newlineLF       = "lf"
+	newlineCRLF     = "crlf"
+	newlinePreserve = "preserve"
+)
+
 var _ resource.Resource = &RepositoryFileResource{}
 var _ resource.ResourceWithImportState = &RepositoryFileResource{}
+var _ resource.ResourceWithModifyPlan = &RepositoryFileResource{}
+var _ resource.ResourceWithValidateConfig = &RepositoryFileResource{}
 
 func NewRepositoryFileResource() resource.Resource {
 	return &RepositoryFileResource{}
@@ -62,43 +139,223 @@ func (r *RepositoryFileResource) Schema(ctx context.Context, req resource.Schema
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"repository": repositoryResourceAttribute(),
 			"branch": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString("main"),
+				Description: "Branch to write the file to, which takes precedence over the provider branch. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"create_branch_if_missing": schema.BoolAttribute{
+				Description: "Creates the branch from the base branch when it does not exist in the remote repository, instead of failing to clone it.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"base_branch": schema.StringAttribute{
+				Description: "Branch which a missing branch is created from when `create_branch_if_missing` is set. Defaults to the default branch of the remote repository.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
 			},
 			"path": schema.StringAttribute{
-				Required: true,
+				Description: "Path of the file relative to the root of the repository, or to the provider path prefix. Backslashes are converted to forward slashes. A warning is shown in the plan when creating or moving a file which is ignored by the `.gitignore` files of the repository, has the `export-ignore` attribute or is tracked by Git LFS.",
+				Required:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
 			},
 			"content": schema.StringAttribute{
-				Required: true,
+				Description: "Content of the file, which has to be text without NUL bytes. Exactly one of `content`, `content_base64`, `content_file`, `sensitive_content` and `source_ref` must be set.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.TextContent(),
+				},
+			},
+			"content_file": schema.StringAttribute{
+				Description: "Path to a local file which is written to the repository, for large files which should not be stored in the state. Changes are detected with the checksum of the file. The content is still held in memory while the blob is written and when the file is read back, as go-git buffers blobs in memory.",
+				Optional:    true,
+			},
+			"source_ref": schema.StringAttribute{
+				Description: "Branch, tag or full commit SHA of the repository which the content of the file is copied from, for example `v1.2.0`. Only the checksum of the content is stored in the state, and changes to the source file are planned when the ref is a branch.",
+				Optional:    true,
+			},
+			"source_path": schema.StringAttribute{
+				Description: "Path of the file which is copied from `source_ref`, relative to the root of the repository or to the provider path prefix. Defaults to `path`.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"source_commit": schema.StringAttribute{
+				Description: "SHA of the commit which `source_ref` resolved to when planning, which the content is copied from during the apply even when the ref has moved since.",
+				Computed:    true,
+			},
+			"content_base64": schema.StringAttribute{
+				Description: "Base64 encoded content of the file, for binary files which are not valid UTF-8.",
+				Optional:    true,
+			},
+			"sensitive_content": schema.StringAttribute{
+				Description: "Content of the file which is not shown in plans, for files containing credentials or large generated files. This is a string attribute used instead of `content` rather than a boolean flag, as Terraform cannot mark `content` sensitive depending on the configuration. Plans only show that the content changed instead of a diff of the content. The content is still stored in the state, so use `content_file` for secrets which must not end up in the state.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					validators.TextContent(),
+				},
+			},
+			"content_sha256": schema.StringAttribute{
+				Description: "SHA256 checksum of the file content. Only the checksum is stored in the state when `content_file` or `source_ref` is used instead of `content`. The checksum is sensitive, as the unsalted checksum of short `sensitive_content` could be used to guess the content.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"commit_sha": schema.StringAttribute{
+				Description: "SHA of the commit created when the file was last written by the provider, or of the head commit of the branch when the file already had the content.",
+				Computed:    true,
+			},
+			"web_url": schema.StringAttribute{
+				Description: "Url where the file can be browsed at `commit_sha`, for repositories hosted on GitHub, GitLab, Bitbucket and Azure DevOps or when the provider `web_url_template` is set.",
+				Computed:    true,
+			},
+			"last_commit_sha": schema.StringAttribute{
+				Description: "SHA of the most recent commit of the branch which changed the file. Null unless `last_commit_depth` is set, or when the file has not been changed within the searched commits.",
+				Computed:    true,
+			},
+			"last_author": schema.StringAttribute{
+				Description: "Author of the most recent commit which changed the file, in the format `Name <email>`. Null when `last_commit_sha` is null.",
+				Computed:    true,
+			},
+			"last_modified": schema.StringAttribute{
+				Description: "RFC3339 timestamp of the most recent commit which changed the file. Null when `last_commit_sha` is null.",
+				Computed:    true,
+			},
+			"last_commit_depth": schema.Int64Attribute{
+				Description: "Number of commits of the branch which are fetched to find the most recent commit which changed the file. The last commit attributes are only set when this is set, as the commits are fetched each time the file is written or read after the branch has changed.",
+				Optional:    true,
 			},
 			"override_on_create": schema.BoolAttribute{
+				Description:   "Overrides an existing file with different content when creating the resource. Existing files with the same content are always adopted.",
 				Optional:      true,
 				Computed:      true,
 				Default:       booldefault.StaticBool(false),
 				PlanModifiers: []planmodifier.Bool{},
 			},
+			"error_if_missing": schema.BoolAttribute{
+				Description: "Fails reading the resource when the file has been removed from the branch outside of Terraform, instead of removing the resource from the state so that the file is created again.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"drift_detection": schema.StringAttribute{
+				Description: "How changes made to the file outside of Terraform are detected. `none` never reads the file. `full` reads the file when the branch has changed. There is no mode comparing blob hashes only, as go-git cannot fetch the tree of a commit without its blobs. Defaults to `full`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(driftDetectionFull),
+				Validators: []validator.String{
+					validators.OneOf(driftDetectionNone, driftDetectionFull),
+				},
+			},
+			"drift_comparison": schema.StringAttribute{
+				Description: "How the file read from the repository is compared with the configured content. `ignore_whitespace` ignores differences in line endings and trailing whitespace of `content` and `sensitive_content`, so that files normalized by the server do not cause changes. Defaults to `exact`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(driftComparisonExact),
+				Validators: []validator.String{
+					validators.OneOf(driftComparisonExact, driftComparisonIgnoreWhitespace),
+				},
+			},
+			"newline": schema.StringAttribute{
+				Description: "Line endings of the committed file. `lf` and `crlf` convert the line endings of `content` and `sensitive_content`, so that content with other line endings does not cause changes to be planned. Defaults to `preserve`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(newlinePreserve),
+				Validators: []validator.String{
+					validators.OneOf(newlineLF, newlineCRLF, newlinePreserve),
+				},
+			},
+			"ensure_trailing_newline": schema.BoolAttribute{
+				Description: "Ends the committed file with exactly one newline, removing additional trailing newlines of `content` and `sensitive_content`. Empty content is left empty.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"bom": schema.StringAttribute{
+				Description: "Handling of the UTF-8 byte order mark of `content` and `sensitive_content`. `strip` removes the byte order mark and `add` adds it to non-empty content, so that files written on Windows do not change between applies. Defaults to `preserve`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(bomPreserve),
+				Validators: []validator.String{
+					validators.OneOf(bomPreserve, bomStrip, bomAdd),
+				},
+			},
+			"executable": schema.BoolAttribute{
+				Description: "Commits the file with mode `100755`, so that scripts can be executed when checked out.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"destroy_strategy": schema.StringAttribute{
+				Description: "What happens to the file when the resource is destroyed. `delete` removes the file from the branch, while `keep` leaves the file in the repository and only removes the resource from the state. The value has to be applied before the resource is removed from the configuration. Defaults to `delete`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(destroyStrategyDelete),
+				Validators: []validator.String{
+					validators.OneOf(destroyStrategyDelete, destroyStrategyKeep),
+				},
+			},
+			"on_remote_change": schema.StringAttribute{
+				Description: "What happens when updating a file which has been modified in the branch since it was last written by Terraform. `overwrite` replaces the changes, `warn` replaces the changes with a warning, and `fail` fails the update so that the changes are not lost. Defaults to `overwrite`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(remoteChangeOverwrite),
+				Validators: []validator.String{
+					validators.OneOf(remoteChangeOverwrite, remoteChangeWarn, remoteChangeFail),
+				},
+			},
 			"author_name": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString("Terraform Provider Git"),
+				Description: "Author name of the commit. Defaults to the provider commits author name.",
+				Optional:    true,
+				Computed:    true,
 			},
 			"author_email": schema.StringAttribute{
-				Optional: true,
+				Description: "Author email of the commit. Defaults to the provider commits author email.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
 			},
 			"message": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString("Write file with Terraform Provider Git."),
+				Description: "Commit message. Defaults to the provider commits message.",
+				Optional:    true,
+				Computed:    true,
 			},
-			"timeouts": timeouts.AttributesAll(ctx),
+			"co_authors": schema.ListAttribute{
+				Description: "Co-authors added as `Co-authored-by` trailers to the commit, in the format `Name <email>`.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"push_options": schema.ListAttribute{
+				Description: "Push options sent to the server when pushing, for example `ci.skip`. Overrides the provider push options. Each key can only be given once, and `merge_request.*` options cannot be set together with `merge_request`.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"refspecs": schema.ListAttribute{
+				Description: "Refspecs used when pushing the commit, for example `HEAD:refs/heads/generated/prod`. The commit is made on the branch, which is pushed to the same branch when not set.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"merge_request": mergeRequestResourceAttribute(),
+			"timeouts":      timeouts.AttributesAll(ctx),
 		},
 	}
 }
@@ -118,6 +375,217 @@ func (r *RepositoryFileResource) Configure(ctx context.Context, req resource.Con
 	r.prd = prd
 }
 
+func (r *RepositoryFileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *RepositoryFileResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Repository != nil {
+		resp.Diagnostics.Append(validateRepository(path.Root("repository"), data.Repository.Url, data.Repository.Ssh, data.Repository.Http)...)
+	}
+	resp.Diagnostics.Append(validatePushOptions(ctx, path.Root("push_options"), data.PushOptions)...)
+	resp.Diagnostics.Append(validateMergeRequest(ctx, data.MergeRequest, data.Branch, data.Refspecs, data.PushOptions)...)
+	if !data.BaseBranch.IsNull() && !data.CreateBranchIfMissing.IsUnknown() && !data.CreateBranchIfMissing.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("base_branch"), "Invalid Attribute Combination", "base_branch can only be set when create_branch_if_missing is enabled.")
+	}
+	if !data.SourcePath.IsNull() && data.SourceRef.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("source_path"), "Invalid Attribute Combination", "source_path can only be set when source_ref is set.")
+	}
+	if !data.LastCommitDepth.IsNull() && !data.LastCommitDepth.IsUnknown() && data.LastCommitDepth.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("last_commit_depth"), "Invalid Value", "Value has to be larger than zero.")
+	}
+
+	set := 0
+	for _, v := range []types.String{data.Content, data.ContentBase64, data.ContentFile, data.SensitiveContent, data.SourceRef} {
+		if v.IsUnknown() {
+			return
+		}
+		if !v.IsNull() {
+			set++
+		}
+	}
+	if set != 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), "Invalid Attribute Combination", "Exactly one of content, content_base64, content_file, sensitive_content and source_ref must be set.")
+	}
+	if !data.ContentBase64.IsNull() {
+		_, err := base64.StdEncoding.DecodeString(data.ContentBase64.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("content_base64"), "Invalid Base64", err.Error())
+		}
+	}
+}
+
+// ModifyPlan sets the commit attributes which are not configured to the
+// provider defaults, so that the plan shows the values which will be used. The
+// checksum of the content is computed so that changes to the content file and
+// the source file are planned. The path and branch are checked against the
+// path policy and the protected branches, and warnings are added when the
+// branch differs from the provider branch or the new path matches the ignore
+// rules of the repository.
+func (r *RepositoryFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.prd == nil {
+		return
+	}
+
+	// The whole plan cannot be read into the model as the repository may be unknown.
+	plan := &RepositoryFileResourceModel{}
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("ensure_trailing_newline"), &plan.EnsureTrailingNewline)...)
+	for name, v := range map[string]*types.String{
+		"content":           &plan.Content,
+		"content_base64":    &plan.ContentBase64,
+		"content_file":      &plan.ContentFile,
+		"sensitive_content": &plan.SensitiveContent,
+		"source_ref":        &plan.SourceRef,
+		"source_path":       &plan.SourcePath,
+		"path":              &plan.Path,
+		"newline":           &plan.Newline,
+		"bom":               &plan.BOM,
+	} {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(name), v)...)
+	}
+	var repo types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("repository"), &repo)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.Path.IsUnknown() {
+		resp.Diagnostics.Append(r.prd.validatePath(path.Root("path"), r.prd.RepositoryPath(plan.Path.ValueString()))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	// The default branch is only resolved for the provider repository.
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("branch"), &plan.Branch)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if repo.IsNull() || plan.Branch.ValueString() != "" {
+		resp.Diagnostics.Append(r.prd.validateBranch(ctx, plan.Branch)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	contentFile := plan.ContentFile
+	checksum := types.StringNull()
+	sourceCommit := types.StringNull()
+	if plan.Content.IsUnknown() || plan.ContentBase64.IsUnknown() || contentFile.IsUnknown() || plan.SensitiveContent.IsUnknown() || plan.SourceRef.IsUnknown() || plan.SourcePath.IsUnknown() {
+		checksum = types.StringUnknown()
+		sourceCommit = types.StringUnknown()
+	} else if !plan.SourceRef.IsNull() {
+		// The source is only read from the provider repository, and may not
+		// exist until the apply.
+		checksum = types.StringUnknown()
+		sourceCommit = types.StringUnknown()
+		if repo.IsNull() && !plan.Path.IsUnknown() {
+			plan.SourceCommit = types.StringNull()
+			_, diags := plan.readSource(ctx, r.prd)
+			if !diags.HasError() {
+				checksum = plan.ContentSHA256
+				sourceCommit = plan.SourceCommit
+			}
+		}
+		// The commit is kept while the content is the same, so that commits to
+		// other files of the source branch do not cause changes to be planned.
+		var stateChecksum, stateCommit types.String
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("content_sha256"), &stateChecksum)...)
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("source_commit"), &stateCommit)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		if !checksum.IsUnknown() && checksum.Equal(stateChecksum) && !stateCommit.IsNull() {
+			sourceCommit = stateCommit
+		}
+	} else if contentFile.IsNull() {
+		checksum = types.StringValue(stringSHA256(plan.writtenContent()))
+	} else {
+		sum, err := fileSHA256(contentFile.ValueString())
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// The file may be created during the apply.
+			checksum = types.StringUnknown()
+		case err != nil:
+			resp.Diagnostics.AddAttributeError(path.Root("content_file"), "Content File Error", err.Error())
+			return
+		default:
+			checksum = types.StringValue(sum)
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_sha256"), checksum)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_commit"), sourceCommit)...)
+
+	// The resource branch takes precedence over the provider branch, which
+	// may not be what was intended when both are configured.
+	var branch types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("branch"), &branch)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if repo.IsNull() && r.prd.branch != "" && branch.ValueString() != "" && branch.ValueString() != r.prd.branch {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("branch"),
+			"Branch Overrides Provider Branch",
+			fmt.Sprintf("The file is written to branch %q instead of the provider branch %q.", branch.ValueString(), r.prd.branch),
+		)
+	}
+
+	// The rules of the repository are only checked when the file is created or
+	// moved, as the path is otherwise known to be committed already.
+	var statePath types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("path"), &statePath)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if repo.IsNull() && !plan.Path.IsUnknown() && !branch.IsUnknown() && !plan.Path.Equal(statePath) {
+		r.addPathWarnings(ctx, branch, plan.Path.ValueString(), &resp.Diagnostics)
+	}
+
+	defaults := map[string]string{
+		"author_name":  r.prd.commitDefaults.authorName,
+		"author_email": r.prd.commitDefaults.authorEmail,
+		"message":      r.prd.commitDefaults.message,
+	}
+	for name, value := range defaults {
+		var v types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &v)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !v.IsNull() {
+			continue
+		}
+		planValue := types.StringNull()
+		if value != "" {
+			planValue = types.StringValue(value)
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), planValue)...)
+	}
+}
+
+// addPathWarnings adds warnings when the path matches the .gitignore or
+// .gitattributes rules of the branch. The rules are not checked when the
+// branch cannot be read, for example as it is created during the apply.
+func (r *RepositoryFileResource) addPathWarnings(ctx context.Context, branch types.String, p string, diags *diag.Diagnostics) {
+	b, err := r.prd.ResolveBranch(ctx, branch)
+	if err != nil {
+		tflog.Debug(ctx, "Could not resolve the branch to check the path against", map[string]interface{}{"error": r.prd.redact(err.Error())})
+		return
+	}
+	warnings, err := r.prd.PathWarnings(ctx, b, r.prd.RepositoryPath(p))
+	if err != nil {
+		tflog.Debug(ctx, "Could not check the path against the rules of the repository", map[string]interface{}{"error": r.prd.redact(err.Error())})
+		return
+	}
+	for _, warning := range warnings {
+		diags.AddAttributeWarning(path.Root("path"), "File Path Warning", warning)
+	}
+}
+
 func (r *RepositoryFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *RepositoryFileResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -133,46 +601,65 @@ func (r *RepositoryFileResource) Create(ctx context.Context, req resource.Create
 	ctx, cancel := context.WithTimeout(ctx, createTimeout)
 	defer cancel()
 
-	commit := git.Commit{
-		Message: data.Message.ValueString(),
-		Author: git.Signature{
-			Name:  data.AuthorName.ValueString(),
-			Email: data.AuthorEmail.ValueString(),
-		},
+	prd, diags := r.resourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	err := retry.RetryContext(ctx, createTimeout, func() *retry.RetryError {
-		files := map[string]io.Reader{
-			data.Path.ValueString(): strings.NewReader(data.Content.ValueString()),
-		}
-		client, err := r.prd.GetGitClient(ctx, data.Branch.ValueString())
-		if err != nil {
-			return retry.NonRetryableError(err)
-		}
-		path := filepath.Join(client.Path(), data.Path.ValueString())
-		_, err = os.Stat(path)
-		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			return retry.NonRetryableError(err)
-		}
-		if err == nil && !data.OverrideOnCreate.ValueBool() {
-			return retry.NonRetryableError(fmt.Errorf("cannot override existing file"))
-		}
-		_, err = client.Commit(commit, repository.WithFiles(files))
-		if err != nil {
-			return retry.NonRetryableError(err)
-		}
-		err = client.Push(ctx, repository.PushConfig{})
-		if err != nil {
-			return retry.RetryableError(err)
-		}
-		return nil
+
+	branch, err := prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", prd.redact(err.Error()))
+		return
+	}
+	data.Branch = types.StringValue(branch)
+
+	commit, diags := data.commit(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pushConfig, diags := data.pushConfig(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	content, blob, diags := data.fileContent(ctx, prd)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd, diags = data.withBaseBranch(ctx, prd)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd, diags = data.withMergeRequest(ctx, prd, branch)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hash, err := prd.ApplyChange(ctx, createTimeout, branch, commit, pushConfig, fileChange{
+		path:     prd.RepositoryPath(data.Path.ValueString()),
+		content:  content,
+		mode:     data.fileMode(),
+		create:   true,
+		override: data.OverrideOnCreate.ValueBool(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Git File Create Error", err.Error())
+		prd.addGitError(&resp.Diagnostics, "Git File Create Error", err)
 		return
 	}
 	data.ID = data.Path
+	data.CommitSHA = types.StringValue(hash)
+	data.WebURL = data.webURL(prd)
+	err = data.setLastCommit(ctx, prd, data.Branch.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddWarning("Last Commit Error", fmt.Sprintf("Could not find the last commit which changed the file: %s", prd.redact(err.Error())))
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setFileHead(ctx, resp.Private, fileHead{URL: prd.url, Path: prd.RepositoryPath(data.Path.ValueString()), Hash: hash, Written: blob})...)
 }
 
 func (r *RepositoryFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -190,26 +677,70 @@ func (r *RepositoryFileResource) Read(ctx context.Context, req resource.ReadRequ
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
-	client, err := r.prd.GetGitClient(ctx, data.Branch.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Git Client Error", err.Error())
+	prd, diags := r.resourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	absPath := filepath.Join(client.Path(), data.ID.ValueString())
-	b, err := os.ReadFile(absPath)
-	if err != nil && errors.Is(err, os.ErrNotExist) {
-		diags = resp.State.SetAttribute(ctx, path.Root("id"), "")
+	repoPath := prd.RepositoryPath(data.ID.ValueString())
+	// The path is not known yet when the resource has been imported.
+	if data.Path.IsNull() {
+		importRef, diags := getImportRef(ctx, req.Private)
 		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		r.readImport(ctx, prd, data, importRef, resp)
+		return
+	}
+	if data.DriftDetection.ValueString() == driftDetectionNone {
+		return
+	}
+	head, diags := getFileHead(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	branch, diags := data.readBranch(ctx, prd)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sameFile := head.URL == prd.url && head.Path == repoPath
+	if head.Hash != "" && sameFile {
+		remoteHead, err := prd.RemoteHead(ctx, branch)
+		if err == nil && remoteHead == head.Hash {
+			tflog.Debug(ctx, "Skipping file read as the branch has not changed", map[string]interface{}{"path": repoPath, "head": remoteHead})
+			return
+		}
+	}
+	hash, file, err := prd.StreamFile(ctx, branch, repoPath, data.readFile)
+	if errors.Is(err, os.ErrNotExist) {
+		if data.ErrorIfMissing.ValueBool() {
+			resp.Diagnostics.AddError("File Doesn't Exist", fmt.Sprintf("File %s has been removed from branch %s.", repoPath, branch))
+			return
+		}
+		tflog.Warn(ctx, "Removing resource from state as the file does not exist", map[string]interface{}{"path": repoPath})
+		resp.State.RemoveResource(ctx)
 		return
 	}
 	if err != nil {
-		resp.Diagnostics.AddError("File Read Error", err.Error())
+		prd.addGitError(&resp.Diagnostics, "File Read Error", err)
 		return
 	}
 	data.Path = data.ID
-	data.Content = types.StringValue(string(b))
+	data.Executable = types.BoolValue(file.Mode == filemode.Executable)
+	err = data.setLastCommit(ctx, prd, branch)
+	if err != nil {
+		tflog.Warn(ctx, "Could not find the last commit which changed the file", map[string]interface{}{"path": repoPath, "error": prd.redact(err.Error())})
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	written := ""
+	if sameFile {
+		written = head.Written
+	}
+	resp.Diagnostics.Append(setFileHead(ctx, resp.Private, fileHead{URL: prd.url, Path: repoPath, Hash: hash, Written: written})...)
 }
 
 func (r *RepositoryFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -227,37 +758,61 @@ func (r *RepositoryFileResource) Update(ctx context.Context, req resource.Update
 	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
 	defer cancel()
 
-	commit := git.Commit{
-		Message: data.Message.ValueString(),
-		Author: git.Signature{
-			Name:  data.AuthorName.ValueString(),
-			Email: data.AuthorEmail.ValueString(),
-		},
+	prd, diags := r.resourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	err := retry.RetryContext(ctx, updateTimeout, func() *retry.RetryError {
-		files := map[string]io.Reader{
-			data.Path.ValueString(): strings.NewReader(data.Content.ValueString()),
-		}
-		client, err := r.prd.GetGitClient(ctx, data.Branch.ValueString())
-		if err != nil {
-			return retry.NonRetryableError(err)
-		}
-		_, err = client.Commit(commit, repository.WithFiles(files))
-		if err != nil {
-			return retry.NonRetryableError(err)
-		}
-		err = client.Push(ctx, repository.PushConfig{})
-		if err != nil {
-			return retry.RetryableError(err)
+
+	commit, diags := data.commit(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pushConfig, diags := data.pushConfig(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	content, blob, diags := data.fileContent(ctx, prd)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd, diags = data.withBaseBranch(ctx, prd)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd, diags = data.withMergeRequest(ctx, prd, data.Branch.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.OnRemoteChange.ValueString() != remoteChangeOverwrite {
+		resp.Diagnostics.Append(data.checkRemoteChange(ctx, prd, req.Private)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
-		return nil
+	}
+	hash, err := prd.ApplyChange(ctx, updateTimeout, data.Branch.ValueString(), commit, pushConfig, fileChange{
+		path:    prd.RepositoryPath(data.Path.ValueString()),
+		content: content,
+		mode:    data.fileMode(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Git File Update Error", err.Error())
+		prd.addGitError(&resp.Diagnostics, "Git File Update Error", err)
 		return
 	}
+	data.CommitSHA = types.StringValue(hash)
+	data.WebURL = data.webURL(prd)
+	err = data.setLastCommit(ctx, prd, data.Branch.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddWarning("Last Commit Error", fmt.Sprintf("Could not find the last commit which changed the file: %s", prd.redact(err.Error())))
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setFileHead(ctx, resp.Private, fileHead{URL: prd.url, Path: prd.RepositoryPath(data.Path.ValueString()), Hash: hash, Written: blob})...)
 }
 
 func (r *RepositoryFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -275,50 +830,528 @@ func (r *RepositoryFileResource) Delete(ctx context.Context, req resource.Delete
 	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
 	defer cancel()
 
-	commit := git.Commit{
-		Message: data.Message.ValueString(),
-		Author: git.Signature{
-			Name:  data.AuthorName.ValueString(),
-			Email: data.AuthorEmail.ValueString(),
-		},
+	prd, diags := r.resourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.DestroyStrategy.ValueString() == destroyStrategyKeep {
+		tflog.Info(ctx, "Keeping file in the repository as the destroy strategy is keep", map[string]interface{}{"path": prd.RepositoryPath(data.Path.ValueString())})
+		return
+	}
+
+	commit, diags := data.commit(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pushConfig, diags := data.pushConfig(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd, diags = data.withMergeRequest(ctx, prd, data.Branch.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	err := retry.RetryContext(ctx, deleteTimeout, func() *retry.RetryError {
-		client, err := r.prd.GetGitClient(ctx, data.Branch.ValueString())
+	_, err := prd.ApplyChange(ctx, deleteTimeout, data.Branch.ValueString(), commit, pushConfig, fileChange{
+		path: prd.RepositoryPath(data.Path.ValueString()),
+	})
+	if err != nil {
+		prd.addGitError(&resp.Diagnostics, "Git File Remove Error", err)
+		return
+	}
+}
+
+// setLastCommit sets the attributes describing the most recent commit which
+// changed the file. The attributes are null when the last commit depth is not
+// set, or when the commit cannot be found within the depth.
+func (m *RepositoryFileResourceModel) setLastCommit(ctx context.Context, prd *ProviderResourceData, branch string) error {
+	m.LastCommitSHA = types.StringNull()
+	m.LastAuthor = types.StringNull()
+	m.LastModified = types.StringNull()
+	if m.LastCommitDepth.IsNull() {
+		return nil
+	}
+	commit, err := prd.LastCommit(ctx, branch, prd.RepositoryPath(m.Path.ValueString()), int(m.LastCommitDepth.ValueInt64()))
+	if err != nil || commit == nil {
+		return err
+	}
+	m.LastCommitSHA = types.StringValue(commit.Hash.String())
+	m.LastAuthor = types.StringValue(fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email))
+	m.LastModified = types.StringValue(commit.Committer.When.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// webURL returns the url where the file can be browsed at the commit, or null
+// when the url is not known.
+func (m *RepositoryFileResourceModel) webURL(prd *ProviderResourceData) types.String {
+	u := prd.WebURL(m.Branch.ValueString(), m.CommitSHA.ValueString(), m.Path.ValueString())
+	if u == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(u)
+}
+
+// readFile sets the content attributes from the content of the file in the
+// repository.
+func (m *RepositoryFileResourceModel) readFile(r io.Reader) error {
+	// The content of files streamed from a local file or copied from another
+	// ref is not stored in the state, only the checksum.
+	if !m.ContentFile.IsNull() || !m.SourceRef.IsNull() {
+		sum, err := readerSHA256(r)
+		m.ContentSHA256 = types.StringValue(sum)
+		return err
+	}
+	b, err := io.ReadAll(r)
+	content := string(b)
+	if m.ignoredDifference(content) {
+		content = m.writtenContent()
+	}
+	switch {
+	case !m.SensitiveContent.IsNull():
+		m.SensitiveContent = m.readContent(m.SensitiveContent, content)
+	case !m.ContentBase64.IsNull():
+		m.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString(b))
+	default:
+		m.Content = m.readContent(m.Content, content)
+	}
+	m.ContentSHA256 = types.StringValue(stringSHA256(content))
+	return err
+}
+
+// ignoredDifference returns true when the drift comparison ignores whitespace
+// and the content only differs from the written content in line endings and
+// trailing whitespace.
+func (m *RepositoryFileResourceModel) ignoredDifference(content string) bool {
+	if m.DriftComparison.ValueString() != driftComparisonIgnoreWhitespace || !m.ContentBase64.IsNull() {
+		return false
+	}
+	if m.Content.IsNull() && m.SensitiveContent.IsNull() {
+		return false
+	}
+	return trimWhitespace(content) == trimWhitespace(m.writtenContent())
+}
+
+// trimWhitespace returns the text with LF line endings, and without trailing
+// whitespace on each line or trailing newlines.
+func trimWhitespace(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// fileContent returns the content of the file to write and the hash of its
+// blob, and sets the checksum of the content. The checksum of the content file
+// may not have been known when planning.
+func (m *RepositoryFileResourceModel) fileContent(ctx context.Context, prd *ProviderResourceData) (func() (io.ReadCloser, error), string, diag.Diagnostics) {
+	if !m.SourceRef.IsNull() {
+		content, diags := m.readSource(ctx, prd)
+		if diags.HasError() {
+			return nil, "", diags
+		}
+		return stringContent(content), plumbing.ComputeHash(plumbing.BlobObject, []byte(content)).String(), diags
+	}
+	diags := m.setContentSHA256()
+	if diags.HasError() {
+		return nil, "", diags
+	}
+	if !m.ContentFile.IsNull() {
+		return localFileContent(m.ContentFile.ValueString()), m.blobHash(), diags
+	}
+	return stringContent(m.writtenContent()), m.blobHash(), diags
+}
+
+// readSource returns the content of the source file at the source commit, or
+// at the commit which the source ref resolves to when the source commit is not
+// known yet, and sets the source commit and the checksum of the content.
+func (m *RepositoryFileResourceModel) readSource(ctx context.Context, prd *ProviderResourceData) (string, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	p := m.Path.ValueString()
+	if !m.SourcePath.IsNull() {
+		p = m.SourcePath.ValueString()
+	}
+	commit := m.SourceCommit.ValueString()
+	if commit == "" {
+		var err error
+		commit, err = prd.ResolveCommit(ctx, "", m.SourceRef.ValueString())
 		if err != nil {
-			return retry.NonRetryableError(err)
+			diags.AddAttributeError(path.Root("source_ref"), "Git Ref Error", prd.redact(err.Error()))
+			return "", diags
 		}
-		path := filepath.Join(client.Path(), data.Path.ValueString())
-		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-			tflog.Debug(ctx, "Skipping file removal as the file does not exist", map[string]interface{}{"path": path})
-			return nil
+	}
+	content := ""
+	_, _, err := prd.StreamFileAtCommit(ctx, commit, prd.RepositoryPath(p), func(r io.Reader) error {
+		b, err := io.ReadAll(r)
+		content = string(b)
+		return err
+	})
+	if err != nil {
+		diags.AddAttributeError(path.Root("source_path"), "Source File Error", prd.redact(err.Error()))
+		return "", diags
+	}
+	m.SourceCommit = types.StringValue(commit)
+	m.ContentSHA256 = types.StringValue(stringSHA256(content))
+	return content, diags
+}
+
+// inlineContent returns the content set in the configuration, which is either
+// the content, the decoded base64 content or the sensitive content.
+func (m *RepositoryFileResourceModel) inlineContent() string {
+	if !m.SensitiveContent.IsNull() {
+		return m.SensitiveContent.ValueString()
+	}
+	if !m.ContentBase64.IsNull() {
+		// The content is validated when validating the configuration.
+		b, _ := base64.StdEncoding.DecodeString(m.ContentBase64.ValueString())
+		return string(b)
+	}
+	return m.Content.ValueString()
+}
+
+// writtenContent returns the configured content as it is written to the
+// repository, with text content normalized.
+func (m *RepositoryFileResourceModel) writtenContent() string {
+	content := m.inlineContent()
+	if !m.ContentBase64.IsNull() {
+		return content
+	}
+	return m.normalize(content)
+}
+
+// readContent returns the content to store in the state for the content read
+// from the repository. The current value is kept when it is written as the
+// same content, so that normalizing the content does not cause changes.
+func (m *RepositoryFileResourceModel) readContent(current types.String, content string) types.String {
+	if !current.IsNull() && !current.IsUnknown() && m.normalize(current.ValueString()) == content {
+		return current
+	}
+	return types.StringValue(content)
+}
+
+// normalize converts the line endings of the text content, ensures that it
+// ends with a newline and strips or adds the byte order mark when configured.
+func (m *RepositoryFileResourceModel) normalize(s string) string {
+	switch m.Newline.ValueString() {
+	case newlineLF:
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+	case newlineCRLF:
+		s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+	}
+	if m.EnsureTrailingNewline.ValueBool() && s != "" {
+		newline := "\n"
+		if strings.HasSuffix(s, "\r\n") || m.Newline.ValueString() == newlineCRLF {
+			newline = "\r\n"
 		}
-		err = os.Remove(path)
-		if err != nil {
-			return retry.NonRetryableError(err)
+		s = strings.TrimRight(s, "\r\n") + newline
+	}
+	switch m.BOM.ValueString() {
+	case bomStrip:
+		s = strings.TrimPrefix(s, utf8BOM)
+	case bomAdd:
+		if s != "" && !strings.HasPrefix(s, utf8BOM) {
+			s = utf8BOM + s
 		}
-		_, err = client.Commit(commit)
+	}
+	return s
+}
+
+// fileMode returns the mode of the file in the tree.
+func (m *RepositoryFileResourceModel) fileMode() filemode.FileMode {
+	if m.Executable.ValueBool() {
+		return filemode.Executable
+	}
+	return filemode.Regular
+}
+
+// blobHash returns the hash of the git blob of the file content, or an empty
+// string when the content file cannot be read.
+func (m *RepositoryFileResourceModel) blobHash() string {
+	if m.ContentFile.IsNull() {
+		return plumbing.ComputeHash(plumbing.BlobObject, []byte(m.writtenContent())).String()
+	}
+	f, err := os.Open(m.ContentFile.ValueString())
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return ""
+	}
+	h := plumbing.NewHasher(plumbing.BlobObject, fi.Size())
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return ""
+	}
+	return h.Sum().String()
+}
+
+// setContentSHA256 sets the checksum of the content.
+func (m *RepositoryFileResourceModel) setContentSHA256() diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if m.ContentFile.IsNull() {
+		m.ContentSHA256 = types.StringValue(stringSHA256(m.writtenContent()))
+		return diags
+	}
+	sum, err := fileSHA256(m.ContentFile.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("content_file"), "Content File Error", err.Error())
+		return diags
+	}
+	m.ContentSHA256 = types.StringValue(sum)
+	return diags
+}
+
+func stringSHA256(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+// fileSHA256 returns the hex encoded SHA256 checksum of the local file.
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return readerSHA256(f)
+}
+
+// readerSHA256 returns the hex encoded SHA256 checksum of the content read.
+func readerSHA256(r io.Reader) (string, error) {
+	h := sha256.New()
+	_, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+var coAuthorRegex = regexp.MustCompile(`^[^<>]+ <[^<>]+>$`)
+
+// resourceData returns the provider resource data with the repository and push
+// options configured in the resource applied.
+func (r *RepositoryFileResource) resourceData(ctx context.Context, data *RepositoryFileResourceModel) (*ProviderResourceData, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	prd, err := r.prd.WithRepository(data.Repository)
+	if err != nil {
+		diags.AddAttributeError(path.Root("repository"), "Invalid Repository Configuration", err.Error())
+		return nil, diags
+	}
+	if data.PushOptions.IsNull() || data.PushOptions.IsUnknown() {
+		return prd, diags
+	}
+	pushOptions := []string{}
+	diags.Append(data.PushOptions.ElementsAs(ctx, &pushOptions, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return prd.WithPushOptions(pushOptions), diags
+}
+
+// checkRemoteChange returns a diagnostic when the blob of the file in the branch
+// is not the blob last written by Terraform, as the file has then been modified
+// outside of Terraform. Files which were not written by Terraform since the
+// written blob was recorded are not checked.
+func (m *RepositoryFileResourceModel) checkRemoteChange(ctx context.Context, prd *ProviderResourceData, p privateState) diag.Diagnostics {
+	head, diags := getFileHead(ctx, p)
+	repoPath := prd.RepositoryPath(m.Path.ValueString())
+	if diags.HasError() || head.Written == "" || head.URL != prd.url || head.Path != repoPath {
+		return diags
+	}
+	_, file, err := prd.StreamFile(ctx, m.Branch.ValueString(), repoPath, nil)
+	if errors.Is(err, os.ErrNotExist) {
+		return diags
+	}
+	if err != nil {
+		prd.addGitError(&diags, "File Read Error", err)
+		return diags
+	}
+	if file.Hash.String() == head.Written {
+		return diags
+	}
+	summary := "File Modified Outside of Terraform"
+	detail := fmt.Sprintf("File %s in branch %s has been modified since it was last written by Terraform.", repoPath, m.Branch.ValueString())
+	if m.OnRemoteChange.ValueString() == remoteChangeFail {
+		diags.AddError(summary, detail+" Set on_remote_change to overwrite to replace the changes.")
+		return diags
+	}
+	diags.AddWarning(summary, detail+" The changes are replaced.")
+	return diags
+}
+
+// withBaseBranch returns resource data which creates missing branches from the
+// base branch when create_branch_if_missing is enabled.
+func (m *RepositoryFileResourceModel) withBaseBranch(ctx context.Context, prd *ProviderResourceData) (*ProviderResourceData, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if !m.CreateBranchIfMissing.ValueBool() {
+		return prd, diags
+	}
+	base := m.BaseBranch.ValueString()
+	if base == "" {
+		var err error
+		base, err = prd.RemoteDefaultBranch(ctx)
 		if err != nil {
-			return retry.NonRetryableError(err)
+			diags.AddAttributeError(path.Root("base_branch"), "Git Branch Error", prd.redact(err.Error()))
+			return nil, diags
 		}
-		err = client.Push(ctx, repository.PushConfig{})
-		if err != nil {
-			return retry.RetryableError(err)
+	}
+	return prd.WithBaseBranch(base), diags
+}
+
+// commit returns the commit information for the resource, adding a
+// Co-authored-by trailer to the message for each co-author.
+func (m *RepositoryFileResourceModel) commit(ctx context.Context) (git.Commit, diag.Diagnostics) {
+	message := m.Message.ValueString()
+	if !m.CoAuthors.IsNull() && !m.CoAuthors.IsUnknown() {
+		coAuthors := []string{}
+		diags := m.CoAuthors.ElementsAs(ctx, &coAuthors, false)
+		if diags.HasError() {
+			return git.Commit{}, diags
+		}
+		for i, coAuthor := range coAuthors {
+			if !coAuthorRegex.MatchString(coAuthor) {
+				diags.AddAttributeError(path.Root("co_authors").AtListIndex(i), "Invalid Co-Author", fmt.Sprintf("Expected co-author to have format Name <email>, got: %q", coAuthor))
+				return git.Commit{}, diags
+			}
+			message = addTrailer(message, "Co-authored-by", coAuthor)
+		}
+	}
+	return git.Commit{
+		Message: message,
+		Author: git.Signature{
+			Name:  m.AuthorName.ValueString(),
+			Email: m.AuthorEmail.ValueString(),
+		},
+	}, nil
+}
+
+func (m *RepositoryFileResourceModel) pushConfig(ctx context.Context) (repository.PushConfig, diag.Diagnostics) {
+	refspecs := []string{}
+	if !m.Refspecs.IsNull() && !m.Refspecs.IsUnknown() {
+		diags := m.Refspecs.ElementsAs(ctx, &refspecs, false)
+		if diags.HasError() {
+			return repository.PushConfig{}, diags
 		}
-		return nil
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Git File Remove Error", err.Error())
-		return
 	}
+	return repository.PushConfig{Refspecs: refspecs}, nil
+}
+
+// importID is the structured import ID of a file, which is given as a JSON
+// object so that the path and ref do not have to be split.
+type importID struct {
+	Branch string `json:"branch"`
+	Path   string `json:"path"`
+	Ref    string `json:"ref"`
 }
 
+// ImportState imports the file from an ID with the format branch:path, where
+// the path can be followed by @ref to import the file at a commit, tag or
+// branch. The ID can also be a JSON object with the branch, path and optional
+// ref, whose ref is kept in the private state until the file is read.
 func (r *RepositoryFileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if strings.HasPrefix(strings.TrimSpace(req.ID), "{") {
+		id := importID{}
+		dec := json.NewDecoder(strings.NewReader(req.ID))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&id)
+		if err != nil || id.Branch == "" || id.Path == "" {
+			resp.Diagnostics.AddError("Invalid ID", `Expected id to be a JSON object such as {"branch": "main", "path": "README.md", "ref": "v1.0.0"}, where ref is optional`)
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("branch"), id.Branch)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id.Path)...)
+		resp.Diagnostics.Append(setImportRef(ctx, resp.Private, id.Ref)...)
+		return
+	}
 	b, p, ok := strings.Cut(req.ID, ":")
 	if !ok {
-		resp.Diagnostics.AddError("Invalid ID", "Expected id to have format branch:path")
+		resp.Diagnostics.AddError("Invalid ID", "Expected id to have format branch:path or branch:path@ref, or to be a JSON object with the branch, path and ref")
+		return
 	}
+	// The ref is split from the path when reading, as it depends on the refs of
+	// the remote repository.
 	diags := resp.State.SetAttribute(ctx, path.Root("branch"), b)
 	resp.Diagnostics.Append(diags...)
 	diags = resp.State.SetAttribute(ctx, path.Root("id"), p)
 	resp.Diagnostics.Append(diags...)
 }
+
+// readImport reads an imported file at the commit which the import ref points
+// to, or at the head of the branch. The ref is split from the ID unless it was
+// imported from a structured ID. The commit attributes are set from the
+// commit, and the file is considered to have been written by Terraform at the
+// commit so that later changes are detected with on_remote_change.
+func (r *RepositoryFileResource) readImport(ctx context.Context, prd *ProviderResourceData, data *RepositoryFileResourceModel, importRef *string, resp *resource.ReadResponse) {
+	p, ref := data.ID.ValueString(), ""
+	if importRef != nil {
+		ref = *importRef
+	} else {
+		var err error
+		p, ref, err = prd.splitImportRef(ctx, p)
+		if err != nil {
+			prd.addGitError(&resp.Diagnostics, "Git Ref Error", err)
+			return
+		}
+	}
+	data.ID = types.StringValue(p)
+	repoPath := prd.RepositoryPath(p)
+	hash, err := prd.ResolveCommit(ctx, data.Branch.ValueString(), ref)
+	if err != nil {
+		prd.addGitError(&resp.Diagnostics, "Git Ref Error", err)
+		return
+	}
+	commit, file, err := prd.StreamFileAtCommit(ctx, hash, repoPath, data.readFile)
+	if errors.Is(err, os.ErrNotExist) {
+		resp.Diagnostics.AddError("File Doesn't Exist", fmt.Sprintf("File %s does not exist at commit %s.", repoPath, hash))
+		return
+	}
+	if err != nil {
+		prd.addGitError(&resp.Diagnostics, "File Read Error", err)
+		return
+	}
+	data.Path = data.ID
+	data.Executable = types.BoolValue(file.Mode == filemode.Executable)
+	data.AuthorName = types.StringValue(commit.Author.Name)
+	data.AuthorEmail = types.StringValue(commit.Author.Email)
+	data.Message = types.StringValue(strings.TrimSpace(commit.Message))
+	data.CommitSHA = types.StringValue(hash)
+	data.WebURL = data.webURL(prd)
+	err = data.setLastCommit(ctx, prd, data.Branch.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, "Could not find the last commit which changed the file", map[string]interface{}{"path": repoPath, "error": prd.redact(err.Error())})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setFileHead(ctx, resp.Private, fileHead{URL: prd.url, Path: repoPath, Hash: hash, Written: file.Hash.String()})...)
+}
+
+// splitImportRef splits the path and ref of an imported file at the last @.
+// Paths may contain @, so the text after it is only a ref when it is the full
+// SHA of a commit or a branch or tag of the repository. Otherwise the whole
+// text is the path. A trailing @ splits off an empty ref, which imports a path
+// ending with @ and a ref name at the head of the branch.
+func (prd *ProviderResourceData) splitImportRef(ctx context.Context, id string) (string, string, error) {
+	i := strings.LastIndex(id, "@")
+	if i == -1 {
+		return id, "", nil
+	}
+	p, ref := id[:i], id[i+1:]
+	if ref == "" || commitRegex.MatchString(ref) {
+		return p, ref, nil
+	}
+	refs, err := prd.ListRefs(ctx)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return "", "", err
+	}
+	for _, r := range refs {
+		if r.Name() == plumbing.NewTagReferenceName(ref) || r.Name() == plumbing.NewBranchReferenceName(ref) || r.Name() == plumbing.ReferenceName(ref) {
+			return p, ref, nil
+		}
+	}
+	return id, "", nil
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fluxcd/pkg/git/repository"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RepositoryFileAbsentResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Branch      types.String   `tfsdk:"branch"`
+	Path        types.String   `tfsdk:"path"`
+	CommitSHA   types.String   `tfsdk:"commit_sha"`
+	AuthorName  types.String   `tfsdk:"author_name"`
+	AuthorEmail types.String   `tfsdk:"author_email"`
+	Message     types.String   `tfsdk:"message"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+var _ resource.Resource = &RepositoryFileAbsentResource{}
+var _ resource.ResourceWithModifyPlan = &RepositoryFileAbsentResource{}
+
+func NewRepositoryFileAbsentResource() resource.Resource {
+	return &RepositoryFileAbsentResource{}
+}
+
+type RepositoryFileAbsentResource struct {
+	prd *ProviderResourceData
+}
+
+func (r *RepositoryFileAbsentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repository_file_absent"
+}
+
+func (r *RepositoryFileAbsentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Repository file absent resource, which ensures that a file does not exist on a branch. The file is removed with a commit when it exists, and removed again when it is restored outside of Terraform. Destroying the resource does not restore the file.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch which the file must not exist on. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the file which must not exist, relative to the root of the repository or to the provider path prefix.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"commit_sha": schema.StringAttribute{
+				Description: "SHA of the commit which removed the file, or of the head commit of the branch when the file did not exist.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"author_name": schema.StringAttribute{
+				Description: "Author name of the commit removing the file. Defaults to the provider commits author name.",
+				Optional:    true,
+			},
+			"author_email": schema.StringAttribute{
+				Description: "Author email of the commit removing the file. Defaults to the provider commits author email.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"message": schema.StringAttribute{
+				Description: "Message of the commit removing the file. Defaults to the provider commits message.",
+				Optional:    true,
+			},
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+func (r *RepositoryFileAbsentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+// ModifyPlan checks the branch against the protected branches, and the path
+// against the path policy.
+func (r *RepositoryFileAbsentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.prd == nil {
+		return
+	}
+
+	var branch, p types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("branch"), &branch)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &p)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.prd.validateBranch(ctx, branch)...)
+	if !p.IsUnknown() {
+		resp.Diagnostics.Append(r.prd.validatePath(path.Root("path"), r.prd.RepositoryPath(p.ValueString()))...)
+	}
+}
+
+func (r *RepositoryFileAbsentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RepositoryFileAbsentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	branch, err := r.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", r.prd.redact(err.Error()))
+		return
+	}
+	data.Branch = types.StringValue(branch)
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s", branch, data.Path.ValueString()))
+
+	// A branch which does not exist cannot contain the file, and is not created.
+	head, err := r.prd.RemoteHead(ctx, branch)
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Branch Error", err)
+		return
+	}
+	if head == "" {
+		data.CommitSHA = types.StringNull()
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	commit := r.prd.commitDefaults.commit(data.Message, data.AuthorName, data.AuthorEmail)
+	hash, err := r.prd.ApplyChange(ctx, createTimeout, branch, commit, repository.PushConfig{}, fileChange{
+		path: r.prd.RepositoryPath(data.Path.ValueString()),
+	})
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git File Remove Error", err)
+		return
+	}
+	data.CommitSHA = types.StringValue(hash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read removes the resource from the state when the file exists, so that the
+// file is removed again by the next apply.
+func (r *RepositoryFileAbsentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RepositoryFileAbsentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	head, err := r.prd.RemoteHead(ctx, data.Branch.ValueString())
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Git Branch Error", err)
+		return
+	}
+	if head == "" {
+		return
+	}
+	p := r.prd.RepositoryPath(data.Path.ValueString())
+	err = r.prd.withHeadRepository(ctx, data.Branch.ValueString(), p, func(repo *extgogit.Repository) error {
+		_, _, err := streamHeadFile(repo, p, nil)
+		return err
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "File Read Error", err)
+		return
+	}
+	tflog.Warn(ctx, "Removing resource from state as the file exists", map[string]interface{}{"branch": data.Branch.ValueString(), "path": p})
+	resp.State.RemoveResource(ctx)
+}
+
+// Update only changes the commit attributes, which are used the next time
+// the file is removed.
+func (r *RepositoryFileAbsentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RepositoryFileAbsentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete does not restore the file.
+func (r *RepositoryFileAbsentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
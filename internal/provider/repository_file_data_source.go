@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RepositoryFileDataSourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Branch           types.String `tfsdk:"branch"`
+	Commit           types.String `tfsdk:"commit"`
+	Path             types.String `tfsdk:"path"`
+	Content          types.String `tfsdk:"content"`
+	Sensitive        types.Bool   `tfsdk:"sensitive"`
+	SensitiveContent types.String `tfsdk:"sensitive_content"`
+}
+
+var _ datasource.DataSource = &RepositoryFileDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &RepositoryFileDataSource{}
+
+func NewRepositoryFileDataSource() datasource.DataSource {
+	return &RepositoryFileDataSource{}
+}
+
+type RepositoryFileDataSource struct {
+	prd *ProviderResourceData
+}
+
+func (d *RepositoryFileDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repository_file"
+}
+
+func (d *RepositoryFileDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Repository file data source",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch to read the file from. Defaults to the provider branch, or the default branch of the remote repository. Conflicts with `commit`.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"commit": schema.StringAttribute{
+				Description: "Full SHA of the commit to read the file at, so that the content does not change when the branch moves. Only the commit is fetched when the server allows it. Defaults to the head commit of the branch.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the file in the repository.",
+				Required:    true,
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "Content of the file, which is null when `sensitive` is set.",
+				Computed:    true,
+			},
+			"sensitive": schema.BoolAttribute{
+				Description: "Returns the content in `sensitive_content` instead of `content`, for files containing credentials. Defaults to `false`.",
+				Optional:    true,
+			},
+			"sensitive_content": schema.StringAttribute{
+				Description: "Content of the file when `sensitive` is set, which is not shown in plans or outputs. It is still stored in the state.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (d *RepositoryFileDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.prd = prd
+}
+
+var commitRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func (d *RepositoryFileDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data *RepositoryFileDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Commit.IsNull() || data.Commit.IsUnknown() {
+		return
+	}
+	if !data.Branch.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("commit"), "Invalid Attribute Combination", "Only one of branch and commit can be set.")
+	}
+	if !commitRegex.MatchString(data.Commit.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("commit"), "Invalid Commit", fmt.Sprintf("Expected the full SHA of a commit, got: %q", data.Commit.ValueString()))
+	}
+}
+
+func (d *RepositoryFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *RepositoryFileDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	repoPath := d.prd.RepositoryPath(data.Path.ValueString())
+	var content []byte
+	read := func(r io.Reader) error {
+		var err error
+		content, err = io.ReadAll(r)
+		return err
+	}
+	if !data.Commit.IsNull() {
+		_, _, err := d.prd.StreamFileAtCommit(ctx, data.Commit.ValueString(), repoPath, read)
+		if err != nil {
+			d.prd.addGitError(&resp.Diagnostics, "File Read Error", err)
+			return
+		}
+		data.Branch = types.StringNull()
+	} else {
+		branch, err := d.prd.ResolveBranch(ctx, data.Branch)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", d.prd.redact(err.Error()))
+			return
+		}
+		hash, _, err := d.prd.StreamFile(ctx, branch, repoPath, read)
+		if err != nil {
+			d.prd.addGitError(&resp.Diagnostics, "File Read Error", err)
+			return
+		}
+		data.Branch = types.StringValue(branch)
+		data.Commit = types.StringValue(hash)
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s", data.Commit.ValueString(), data.Path.ValueString()))
+	data.Content = types.StringValue(string(content))
+	data.SensitiveContent = types.StringNull()
+	if data.Sensitive.ValueBool() {
+		data.Content = types.StringNull()
+		data.SensitiveContent = types.StringValue(string(content))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RepositoryFilesDataSourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	Branch types.String `tfsdk:"branch"`
+	Path   types.String `tfsdk:"path"`
+	Commit types.String `tfsdk:"commit"`
+	Files  types.List   `tfsdk:"files"`
+}
+
+var _ datasource.DataSource = &RepositoryFilesDataSource{}
+
+func NewRepositoryFilesDataSource() datasource.DataSource {
+	return &RepositoryFilesDataSource{}
+}
+
+type RepositoryFilesDataSource struct {
+	prd *ProviderResourceData
+}
+
+func (d *RepositoryFilesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repository_files"
+}
+
+func (d *RepositoryFilesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Repository files data source, which lists the files below a path of a branch, for example to generate `import` blocks for existing files.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch to list the files of. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the file or directory to list. Defaults to the whole repository, or the provider path prefix.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"commit": schema.StringAttribute{
+				Description: "SHA of the head commit of the branch, which is empty for an empty repository.",
+				Computed:    true,
+			},
+			"files": schema.ListAttribute{
+				Description: "Sorted paths of the files, relative to the provider path prefix like the `path` of `git_repository_file`.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *RepositoryFilesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.prd = prd
+}
+
+func (d *RepositoryFilesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *RepositoryFilesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	branch, err := d.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", d.prd.redact(err.Error()))
+		return
+	}
+	hash, names, err := d.prd.ListFiles(ctx, branch, d.prd.RepositoryPath(data.Path.ValueString()))
+	if err != nil {
+		d.prd.addGitError(&resp.Diagnostics, "Git Files Error", err)
+		return
+	}
+	if d.prd.pathPrefix != "" {
+		for i, name := range names {
+			names[i] = strings.TrimPrefix(name, d.prd.pathPrefix+"/")
+		}
+	}
+	files, diags := types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s", branch, data.Path.ValueString()))
+	data.Branch = types.StringValue(branch)
+	data.Commit = types.StringValue(hash)
+	data.Files = files
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
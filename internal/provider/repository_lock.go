@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// repositoryLocks serializes the operations on a branch of a repository, from
+// cloning until pushing, so that concurrent resources do not reject each
+// other's pushes. Cached working copies are also only used by one operation
+// at a time.
+type repositoryLocks struct {
+	mu    sync.Mutex
+	locks map[string]semaphore
+}
+
+func newRepositoryLocks() *repositoryLocks {
+	return &repositoryLocks{
+		locks: map[string]semaphore{},
+	}
+}
+
+// Lock blocks until the branch of the repository is locked or the context is
+// done, and returns a function which unlocks it.
+func (l *repositoryLocks) Lock(ctx context.Context, repoURL, branch string) (func(), error) {
+	key := repoURL + "\x00" + branch
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = newSemaphore(1)
+		l.locks[key] = lock
+	}
+	l.mu.Unlock()
+
+	err := lock.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Release, nil
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RepositoryPathDataSourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Path types.String `tfsdk:"path"`
+	Safe types.String `tfsdk:"safe"`
+}
+
+var _ datasource.DataSource = &RepositoryPathDataSource{}
+
+func NewRepositoryPathDataSource() datasource.DataSource {
+	return &RepositoryPathDataSource{}
+}
+
+// RepositoryPathDataSource cleans paths with the same rules as the path
+// validation of git_repository_file, so that modules can build paths from
+// variables without reading the repository.
+type RepositoryPathDataSource struct{}
+
+func (d *RepositoryPathDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repository_path"
+}
+
+func (d *RepositoryPathDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Repository path data source, which cleans a path built in a module into a path relative to the repository root that the `path` of `git_repository_file` accepts. Paths outside of the repository fail at plan time.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"path": schema.StringAttribute{
+				Description: "Path to clean, for example `/clusters//dev/../prod\\app.yaml`. Windows separators are converted, leading slashes are removed and `.` and `..` components are resolved. The path cannot point outside of the repository, at its root or into the `.git` directory.",
+				Required:    true,
+			},
+			"safe": schema.StringAttribute{
+				Description: "Cleaned path relative to the repository root, for example `clusters/prod/app.yaml`.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *RepositoryPathDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *RepositoryPathDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	safe, reason := validators.SanitizePath(data.Path.ValueString())
+	if reason != "" {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Invalid Path", fmt.Sprintf("Path %q %s.", data.Path.ValueString(), reason))
+		return
+	}
+	data.Safe = types.StringValue(safe)
+	data.ID = types.StringValue(safe)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
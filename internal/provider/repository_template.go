@@ -0,0 +1,460 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RepositoryTemplateResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Branch      types.String   `tfsdk:"branch"`
+	Path        types.String   `tfsdk:"path"`
+	SourceDir   types.String   `tfsdk:"source_dir"`
+	Vars        types.Map      `tfsdk:"vars"`
+	Exclude     types.List     `tfsdk:"exclude"`
+	Files       types.Map      `tfsdk:"files"`
+	CommitSHA   types.String   `tfsdk:"commit_sha"`
+	AuthorName  types.String   `tfsdk:"author_name"`
+	AuthorEmail types.String   `tfsdk:"author_email"`
+	Message     types.String   `tfsdk:"message"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+var _ resource.Resource = &RepositoryTemplateResource{}
+var _ resource.ResourceWithModifyPlan = &RepositoryTemplateResource{}
+
+func NewRepositoryTemplateResource() resource.Resource {
+	return &RepositoryTemplateResource{}
+}
+
+type RepositoryTemplateResource struct {
+	prd *ProviderResourceData
+}
+
+func (r *RepositoryTemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repository_template"
+}
+
+func (r *RepositoryTemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Repository template resource, which renders a directory of templates and commits the result to a directory of the repository in a single commit.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch to write the files to. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Directory of the repository which the rendered files are written to, relative to the root of the repository or to the provider path prefix.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.RelativePath(),
+				},
+			},
+			"source_dir": schema.StringAttribute{
+				Description: "Local directory containing the templates. Files with the `.tmpl` suffix are rendered as Go templates with the variables and written without the suffix, while other files are copied as is.",
+				Required:    true,
+			},
+			"vars": schema.MapAttribute{
+				Description: "Variables available in the templates, for example `{{ .name }}`. Rendering fails when a template refers to a variable which is not set.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"exclude": schema.ListAttribute{
+				Description: "Gitignore style patterns of files and directories in the source directory which are not written, for example `.terraform/` or `*.bak`. They are added to the patterns in the `.sourceignore` file in the root of the source directory, which is never written itself.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"files": schema.MapAttribute{
+				Description: "SHA256 checksums of the written files by their path relative to `path`.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"commit_sha": schema.StringAttribute{
+				Description: "SHA of the commit created when the files were last written, or of the head commit of the branch when the files already had the content.",
+				Computed:    true,
+			},
+			"author_name": schema.StringAttribute{
+				Description: "Author name of the commit. Defaults to the provider commits author name.",
+				Optional:    true,
+			},
+			"author_email": schema.StringAttribute{
+				Description: "Author email of the commit. Defaults to the provider commits author email.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.Email(),
+				},
+			},
+			"message": schema.StringAttribute{
+				Description: "Commit message. Defaults to the provider commits message.",
+				Optional:    true,
+			},
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+func (r *RepositoryTemplateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+// ModifyPlan renders the templates so that changes to the templates and
+// variables are planned as changes of the file checksums, and checks the branch
+// against the protected branches and the paths of the rendered files against
+// the path policy.
+func (r *RepositoryTemplateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	plan := &RepositoryTemplateResourceModel{}
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_dir"), &plan.SourceDir)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("vars"), &plan.Vars)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("exclude"), &plan.Exclude)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &plan.Path)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("branch"), &plan.Branch)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if r.prd != nil {
+		resp.Diagnostics.Append(r.prd.validateBranch(ctx, plan.Branch)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	// The source directory may be created during the apply.
+	files := types.MapUnknown(types.StringType)
+	_, err := os.Stat(plan.SourceDir.ValueString())
+	if !plan.SourceDir.IsUnknown() && !plan.Vars.IsUnknown() && !plan.Exclude.IsUnknown() && !errors.Is(err, os.ErrNotExist) {
+		rendered, diags := plan.render(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if r.prd != nil && !plan.Path.IsUnknown() {
+			for _, name := range sortedKeys(rendered) {
+				resp.Diagnostics.Append(r.prd.validatePath(path.Root("path"), plan.filePath(r.prd, name))...)
+			}
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		files, diags = checksums(ctx, rendered)
+		resp.Diagnostics.Append(diags...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("files"), files)...)
+
+	// The commit is only known when the files have not changed.
+	var state types.Map
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("files"), &state)...)
+	}
+	if !req.State.Raw.IsNull() && files.Equal(state) {
+		var commitSHA types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("commit_sha"), &commitSHA)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("commit_sha"), commitSHA)...)
+	}
+}
+
+func (r *RepositoryTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RepositoryTemplateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	branch, err := r.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", r.prd.redact(err.Error()))
+		return
+	}
+	data.Branch = types.StringValue(branch)
+	resp.Diagnostics.Append(r.write(ctx, createTimeout, data, types.MapNull(types.StringType))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s", branch, data.Path.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RepositoryTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	files := map[string]string{}
+	resp.Diagnostics.Append(data.Files.ElementsAs(ctx, &files, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// The checksums of files which were changed outside of Terraform are
+	// updated, and removed files are left out, so that they are written again.
+	err := r.prd.withHeadRepository(ctx, data.Branch.ValueString(), data.Path.ValueString(), func(repo *extgogit.Repository) error {
+		for name := range files {
+			_, _, err := streamHeadFile(repo, data.filePath(r.prd, name), func(r io.Reader) error {
+				sum, err := readerSHA256(r)
+				files[name] = sum
+				return err
+			})
+			if errors.Is(err, os.ErrNotExist) {
+				delete(files, name)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		tflog.Warn(ctx, "Removing resource from state as the branch does not contain any commits", map[string]interface{}{"branch": data.Branch.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		r.prd.addGitError(&resp.Diagnostics, "Files Read Error", err)
+		return
+	}
+	if len(files) == 0 {
+		tflog.Warn(ctx, "Removing resource from state as none of the files exist", map[string]interface{}{"path": data.Path.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	filesValue, diags := types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Files = filesValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RepositoryTemplateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state *RepositoryTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Only the commit attributes have changed when the files are the same.
+	if !data.Files.IsUnknown() && data.Files.Equal(state.Files) {
+		data.CommitSHA = state.CommitSHA
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+	resp.Diagnostics.Append(r.write(ctx, updateTimeout, data, state.Files)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RepositoryTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	files := map[string]string{}
+	resp.Diagnostics.Append(data.Files.ElementsAs(ctx, &files, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	changes := []fileChange{}
+	for _, name := range sortedKeys(files) {
+		changes = append(changes, fileChange{path: data.filePath(r.prd, name)})
+	}
+	_, errs := r.prd.commitChanges(ctx, deleteTimeout, data.Branch.ValueString(), data.commit(r.prd), repository.PushConfig{}, changes)
+	for _, err := range errs {
+		if err != nil {
+			r.prd.addGitError(&resp.Diagnostics, "Git Files Remove Error", err)
+			return
+		}
+	}
+}
+
+// write renders the templates and commits the files in a single commit,
+// removing the previously written files which are no longer rendered. The
+// checksums of the files and the commit are set in the model.
+func (r *RepositoryTemplateResource) write(ctx context.Context, timeout time.Duration, data *RepositoryTemplateResourceModel, previous types.Map) diag.Diagnostics {
+	rendered, diags := data.render(ctx)
+	if diags.HasError() {
+		return diags
+	}
+	previousFiles := map[string]string{}
+	if !previous.IsNull() {
+		diags.Append(previous.ElementsAs(ctx, &previousFiles, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	changes := []fileChange{}
+	for _, name := range sortedKeys(rendered) {
+		changes = append(changes, fileChange{
+			path:    data.filePath(r.prd, name),
+			content: stringContent(rendered[name]),
+		})
+	}
+	for _, name := range sortedKeys(previousFiles) {
+		if _, ok := rendered[name]; !ok {
+			changes = append(changes, fileChange{path: data.filePath(r.prd, name)})
+		}
+	}
+	hash, errs := r.prd.commitChanges(ctx, timeout, data.Branch.ValueString(), data.commit(r.prd), repository.PushConfig{}, changes)
+	for _, err := range errs {
+		if err != nil {
+			r.prd.addGitError(&diags, "Git Files Write Error", err)
+			return diags
+		}
+	}
+
+	files, d := checksums(ctx, rendered)
+	diags.Append(d...)
+	data.Files = files
+	data.CommitSHA = types.StringValue(hash)
+	return diags
+}
+
+// render renders the templates of the source directory.
+func (m *RepositoryTemplateResourceModel) render(ctx context.Context) (map[string]string, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	vars := map[string]string{}
+	if !m.Vars.IsNull() {
+		diags.Append(m.Vars.ElementsAs(ctx, &vars, false)...)
+	}
+	exclude := []string{}
+	if !m.Exclude.IsNull() {
+		diags.Append(m.Exclude.ElementsAs(ctx, &exclude, false)...)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+	rendered, err := renderTemplates(m.SourceDir.ValueString(), vars, exclude)
+	if err != nil {
+		diags.AddAttributeError(path.Root("source_dir"), "Template Error", err.Error())
+		return nil, diags
+	}
+	return rendered, diags
+}
+
+// filePath returns the path in the repository of the rendered file.
+func (m *RepositoryTemplateResourceModel) filePath(prd *ProviderResourceData, name string) string {
+	return prd.RepositoryPath(strings.TrimRight(slashPath(m.Path.ValueString()), "/") + "/" + name)
+}
+
+// commit returns the commit information, falling back to the provider
+// defaults for attributes which are not set.
+func (m *RepositoryTemplateResourceModel) commit(prd *ProviderResourceData) git.Commit {
+	return prd.commitDefaults.commit(m.Message, m.AuthorName, m.AuthorEmail)
+}
+
+// checksums returns the SHA256 checksums of the rendered files.
+func checksums(ctx context.Context, rendered map[string]string) (types.Map, diag.Diagnostics) {
+	sums := map[string]string{}
+	for name, content := range rendered {
+		sums[name] = stringSHA256(content)
+	}
+	return types.MapValueFrom(ctx, types.StringType, sums)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
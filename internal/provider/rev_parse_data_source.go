@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/xenitab/terraform-provider-git/internal/framework/validators"
+)
+
+type RevParseDataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Revision types.String `tfsdk:"revision"`
+	Branch   types.String `tfsdk:"branch"`
+	SHA      types.String `tfsdk:"sha"`
+}
+
+var _ datasource.DataSource = &RevParseDataSource{}
+
+func NewRevParseDataSource() datasource.DataSource {
+	return &RevParseDataSource{}
+}
+
+type RevParseDataSource struct {
+	prd *ProviderResourceData
+}
+
+func (d *RevParseDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rev_parse"
+}
+
+func (d *RevParseDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Rev parse data source, which resolves a revision to the full SHA of a commit like `git rev-parse`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"revision": schema.StringAttribute{
+				Description: "Revision to resolve, for example a branch, a tag, `HEAD~2`, `v1.0.0^` or an abbreviated SHA.",
+				Required:    true,
+			},
+			"branch": schema.StringAttribute{
+				Description: "Branch which `HEAD` refers to, and whose history abbreviated SHAs and revisions starting with a tag are resolved in. Revisions starting with another branch are resolved in the history of that branch. Defaults to the provider branch, or the default branch of the remote repository.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.BranchName(),
+				},
+			},
+			"sha": schema.StringAttribute{
+				Description: "Full SHA of the commit.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *RevParseDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*ProviderResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.prd = prd
+}
+
+func (d *RevParseDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *RevParseDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	branch, err := d.prd.ResolveBranch(ctx, data.Branch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("branch"), "Git Branch Error", d.prd.redact(err.Error()))
+		return
+	}
+	sha, err := d.prd.RevParse(ctx, branch, data.Revision.ValueString())
+	if err != nil {
+		d.prd.addGitError(&resp.Diagnostics, "Git Revision Error", err)
+		return
+	}
+	data.SHA = types.StringValue(sha)
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s", branch, data.Revision.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// RevParse returns the full SHA of the commit which the revision resolves to.
+// Revisions which are a ref are resolved from the remote references, while
+// other revisions are resolved in the fetched history of the branch, or of the
+// branch which the revision starts with.
+func (prd *ProviderResourceData) RevParse(ctx context.Context, branch, rev string) (string, error) {
+	if commitRegex.MatchString(rev) {
+		return rev, nil
+	}
+	base, suffix := rev, ""
+	if i := strings.IndexAny(rev, "~^@"); i != -1 {
+		base, suffix = rev[:i], rev[i:]
+	}
+	if base == "" || base == "HEAD" {
+		base = branch
+	}
+
+	refs, err := prd.ListRefs(ctx)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return "", err
+	}
+	hash, err := prd.ResolveCommit(ctx, branch, base)
+	isRef := err == nil
+	if isRef && suffix == "" {
+		return hash, nil
+	}
+	for _, ref := range refs {
+		if ref.Name() == plumbing.NewBranchReferenceName(base) {
+			branch = base
+		}
+	}
+
+	repo, err := prd.fetchBranch(ctx, branch, 0)
+	if err != nil {
+		return "", err
+	}
+	if isRef && !plumbing.IsHash(base) {
+		// Tags are not fetched, and are only known when they point into the history.
+		err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(base), plumbing.NewHash(hash)))
+		if err != nil {
+			return "", err
+		}
+	}
+	revision := rev
+	if base == branch {
+		revision = plumbing.NewBranchReferenceName(branch).String() + suffix
+	}
+	resolved, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return "", fmt.Errorf("revision %s could not be resolved in the history of branch %s: %w", rev, branch, err)
+	}
+	return resolved.String(), nil
+}
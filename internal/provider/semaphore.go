@@ -0,0 +1,34 @@
+package provider
+
+import "context"
+
+// semaphore limits the number of concurrent operations. A nil semaphore does
+// not impose any limit.
+type semaphore chan struct{}
+
+func newSemaphore(n int64) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+// Acquire blocks until the operation is allowed to run or the context is done.
+func (s semaphore) Acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) Release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
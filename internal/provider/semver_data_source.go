@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SemverDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Versions   types.List   `tfsdk:"versions"`
+	Constraint types.String `tfsdk:"constraint"`
+	Sorted     types.List   `tfsdk:"sorted"`
+	Highest    types.String `tfsdk:"highest"`
+}
+
+var _ datasource.DataSource = &SemverDataSource{}
+
+func NewSemverDataSource() datasource.DataSource {
+	return &SemverDataSource{}
+}
+
+// SemverDataSource orders versions such as tag names by semantic version
+// precedence, which differs from the lexical order in which refs are listed.
+type SemverDataSource struct{}
+
+func (d *SemverDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_semver"
+}
+
+func (d *SemverDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Semver data source, which sorts a list of versions such as tag names and filters them by a constraint. The versions can come from anywhere, for example a variable or the tags listed by another provider.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"versions": schema.ListAttribute{
+				Description: "Versions to sort, for example `[\"v1.2.0\", \"v1.10.0\"]`. A `v` prefix is allowed, and values which are not semantic versions are left out.",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"constraint": schema.StringAttribute{
+				Description: "Constraint which the versions have to match, for example `^1.2` or `>= 1.0, < 2.0`, where `~1.2` matches patch releases of 1.2 only. Pre-releases only match constraints which include a pre-release.",
+				Optional:    true,
+			},
+			"sorted": schema.ListAttribute{
+				Description: "Matching versions from lowest to highest, as they were given.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"highest": schema.StringAttribute{
+				Description: "Highest matching version as it was given, or null when no version matches.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *SemverDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *SemverDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	versions := []string{}
+	resp.Diagnostics.Append(data.Versions.ElementsAs(ctx, &versions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var constraint *semver.Constraints
+	if !data.Constraint.IsNull() {
+		c, err := semver.NewConstraint(data.Constraint.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("constraint"), "Invalid Version Constraint", err.Error())
+			return
+		}
+		constraint = c
+	}
+
+	sorted := sortVersions(versions, constraint)
+	sortedValue, diags := types.ListValueFrom(ctx, types.StringType, sorted)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Sorted = sortedValue
+	data.Highest = types.StringNull()
+	if len(sorted) > 0 {
+		data.Highest = types.StringValue(sorted[len(sorted)-1])
+	}
+	data.ID = types.StringValue(data.Constraint.ValueString())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// sortVersions returns the semantic versions which match the constraint from
+// lowest to highest, keeping the order of equal versions. Values which are not
+// semantic versions are left out, and a nil constraint matches all versions.
+func sortVersions(versions []string, constraint *semver.Constraints) []string {
+	type parsed struct {
+		name    string
+		version *semver.Version
+	}
+	matching := []parsed{}
+	for _, name := range versions {
+		v, err := semver.NewVersion(name)
+		if err != nil {
+			continue
+		}
+		if constraint != nil && !constraint.Check(v) {
+			continue
+		}
+		matching = append(matching, parsed{name: name, version: v})
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].version.LessThan(matching[j].version)
+	})
+	sorted := []string{}
+	for _, m := range matching {
+		sorted = append(sorted, m.name)
+	}
+	return sorted
+}
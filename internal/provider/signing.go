@@ -0,0 +1,426 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	signingFormatOpenPGP = "openpgp"
+	signingFormatSSH     = "ssh"
+)
+
+// sshSignatureNamespace is the namespace used by git for SSH commit signatures.
+const sshSignatureNamespace = "git"
+
+// signer signs commits with either an OpenPGP or an SSH key.
+type signer struct {
+	entity    *openpgp.Entity
+	sshSigner ssh.Signer
+}
+
+func newSigner(s *Signing) (*signer, error) {
+	if s == nil {
+		return nil, nil
+	}
+	switch s.Format.ValueString() {
+	case "", signingFormatOpenPGP:
+		entity, err := newOpenPGPEntity(s.PrivateKey.ValueString(), s.Passphrase.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		return &signer{entity: entity}, nil
+	case signingFormatSSH:
+		var sshSigner ssh.Signer
+		var err error
+		if s.Passphrase.ValueString() == "" {
+			sshSigner, err = ssh.ParsePrivateKey([]byte(s.PrivateKey.ValueString()))
+		} else {
+			sshSigner, err = ssh.ParsePrivateKeyWithPassphrase([]byte(s.PrivateKey.ValueString()), []byte(s.Passphrase.ValueString()))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read private key: %w", err)
+		}
+		return &signer{sshSigner: sshSigner}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing format %q", s.Format.ValueString())
+	}
+}
+
+// newOpenPGPEntity returns the OpenPGP entity used to sign commits, decrypting
+// the private keys with the passphrase when they are encrypted.
+func newOpenPGPEntity(privateKey, passphrase string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("could not read private key: %w", err)
+	}
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected exactly one key, got %d", len(entities))
+	}
+	entity := entities[0]
+	if entity.PrivateKey == nil {
+		return nil, fmt.Errorf("key does not contain a private key")
+	}
+
+	if entity.PrivateKey.Encrypted {
+		err := entity.PrivateKey.Decrypt([]byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt private key: %w", err)
+		}
+	}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey == nil || !subkey.PrivateKey.Encrypted {
+			continue
+		}
+		err := subkey.PrivateKey.Decrypt([]byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt private subkey: %w", err)
+		}
+	}
+	return entity, nil
+}
+
+// sign returns the signature of the commit.
+func (s *signer) sign(commit *object.Commit) (string, error) {
+	encoded := &plumbing.MemoryObject{}
+	err := commit.EncodeWithoutSignature(encoded)
+	if err != nil {
+		return "", err
+	}
+	r, err := encoded.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	if s.entity != nil {
+		b := &bytes.Buffer{}
+		err := openpgp.ArmoredDetachSign(b, s.entity, r, nil)
+		if err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	message, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	signature, err := sshSign(message, s.sshSigner)
+	if err != nil {
+		return "", err
+	}
+	return string(signature), nil
+}
+
+// signHeadSSH replaces the HEAD commit with a copy signed with the SSH key, as
+// go-git is only able to sign commits with OpenPGP keys. The hash of the signed
+// commit is returned.
+func signHeadSSH(repo *extgogit.Repository, sshSigner ssh.Signer) (string, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+
+	payload := repo.Storer.NewEncodedObject()
+	err = commit.EncodeWithoutSignature(payload)
+	if err != nil {
+		return "", err
+	}
+	r, err := payload.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	message, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	signature, err := sshSign(message, sshSigner)
+	if err != nil {
+		return "", err
+	}
+	commit.PGPSignature = string(signature)
+
+	obj := repo.Storer.NewEncodedObject()
+	err = commit.Encode(obj)
+	if err != nil {
+		return "", err
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return "", err
+	}
+	err = repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), hash))
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// sshSign returns an armored signature of the message in the format created by
+// `ssh-keygen -Y sign`.
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+func sshSign(message []byte, sshSigner ssh.Signer) ([]byte, error) {
+	h := sha512.Sum512(message)
+	signedData := struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          string
+	}{
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          string(h[:]),
+	}
+	data := append([]byte("SSHSIG"), ssh.Marshal(signedData)...)
+
+	var sig *ssh.Signature
+	var err error
+	if algorithmSigner, ok := sshSigner.(ssh.AlgorithmSigner); ok && sshSigner.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// SHA-1 RSA signatures are rejected by git.
+		sig, err = algorithmSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = sshSigner.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := struct {
+		Version       uint32
+		PublicKey     string
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     string
+	}{
+		Version:       1,
+		PublicKey:     string(sshSigner.PublicKey().Marshal()),
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     string(ssh.Marshal(sig)),
+	}
+	encoded := base64.StdEncoding.EncodeToString(append([]byte("SSHSIG"), ssh.Marshal(blob)...))
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("-----BEGIN SSH SIGNATURE-----\n")
+	for len(encoded) > 70 {
+		buf.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	buf.WriteString(encoded + "\n")
+	buf.WriteString("-----END SSH SIGNATURE-----\n")
+	return buf.Bytes(), nil
+}
+
+// ErrUntrustedHead is returned when the head commit of the branch is not signed
+// with one of the trusted keys.
+var ErrUntrustedHead = errors.New("head commit is not signed with a trusted key")
+
+// verifier verifies the signatures of commits with the trusted keys.
+type verifier struct {
+	keyRing openpgp.EntityList
+	sshKeys []ssh.PublicKey
+}
+
+func newVerifier(ctx context.Context, v *VerifyHead) (*verifier, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if v == nil {
+		return nil, diags
+	}
+	result := &verifier{}
+	if v.OpenPGPPublicKeys.ValueString() != "" {
+		keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(v.OpenPGPPublicKeys.ValueString()))
+		if err != nil {
+			diags.AddAttributeError(path.Root("verify_head").AtName("openpgp_public_keys"), "Invalid Public Key", fmt.Sprintf("Could not read public keys: %s", err))
+			return nil, diags
+		}
+		result.keyRing = keyRing
+	}
+	if !v.SSHPublicKeys.IsNull() && !v.SSHPublicKeys.IsUnknown() {
+		keys := []string{}
+		diags.Append(v.SSHPublicKeys.ElementsAs(ctx, &keys, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		for i, key := range keys {
+			pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+			if err != nil {
+				diags.AddAttributeError(path.Root("verify_head").AtName("ssh_public_keys").AtListIndex(i), "Invalid Public Key", fmt.Sprintf("Could not read public key: %s", err))
+				return nil, diags
+			}
+			result.sshKeys = append(result.sshKeys, pub)
+		}
+	}
+	if len(result.keyRing) == 0 && len(result.sshKeys) == 0 {
+		diags.AddAttributeError(path.Root("verify_head"), "Missing Public Keys", "At least one OpenPGP or SSH public key has to be trusted.")
+		return nil, diags
+	}
+	return result, diags
+}
+
+// trusts returns an error when commits signed by the signer would not be
+// trusted, as the commits of the provider would then become untrusted heads
+// which it cannot commit on top of.
+func (v *verifier) trusts(s *signer) error {
+	if s == nil {
+		return errors.New("commits are not signed, so signing has to be configured with a trusted key")
+	}
+	if s.sshSigner != nil {
+		pub := s.sshSigner.PublicKey()
+		for _, key := range v.sshKeys {
+			if bytes.Equal(key.Marshal(), pub.Marshal()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("signing key %s is not among the trusted SSH public keys", ssh.FingerprintSHA256(pub))
+	}
+	key, ok := s.entity.SigningKey(time.Now())
+	if !ok {
+		return errors.New("signing key cannot be used to sign")
+	}
+	if len(v.keyRing.KeysById(key.PublicKey.KeyId)) == 0 {
+		return fmt.Errorf("signing key %s is not among the trusted OpenPGP public keys", key.PublicKey.KeyIdString())
+	}
+	return nil
+}
+
+// verifyHead returns an error when the HEAD commit of the repository is not
+// signed with one of the trusted keys. Repositories without commits are
+// trusted, as there is no history to extend.
+func (v *verifier) verifyHead(repo *extgogit.Repository) error {
+	if v == nil {
+		return nil
+	}
+	commit, err := headCommit(repo)
+	if err != nil || commit == nil {
+		return err
+	}
+	err = v.verify(commit)
+	if err != nil {
+		return fmt.Errorf("refusing to commit on top of %s: %w: %s", commit.Hash, ErrUntrustedHead, err)
+	}
+	return nil
+}
+
+// verify returns an error when the commit is not signed with one of the
+// trusted keys.
+func (v *verifier) verify(commit *object.Commit) error {
+	if commit.PGPSignature == "" {
+		return errors.New("commit is not signed")
+	}
+	encoded := &plumbing.MemoryObject{}
+	err := commit.EncodeWithoutSignature(encoded)
+	if err != nil {
+		return err
+	}
+	r, err := encoded.Reader()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if strings.HasPrefix(commit.PGPSignature, "-----BEGIN SSH SIGNATURE-----") {
+		message, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return sshVerify(message, commit.PGPSignature, v.sshKeys)
+	}
+	if len(v.keyRing) == 0 {
+		return errors.New("commit is signed with an OpenPGP key and no OpenPGP keys are trusted")
+	}
+	_, err = openpgp.CheckArmoredDetachedSignature(v.keyRing, r, strings.NewReader(commit.PGPSignature), nil)
+	return err
+}
+
+// sshVerify verifies the armored signature of the message created by
+// `ssh-keygen -Y sign`, which has to be signed with one of the keys.
+func sshVerify(message []byte, armored string, keys []ssh.PublicKey) error {
+	encoded := strings.TrimSpace(armored)
+	encoded = strings.TrimPrefix(encoded, "-----BEGIN SSH SIGNATURE-----")
+	encoded = strings.TrimSuffix(encoded, "-----END SSH SIGNATURE-----")
+	b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return fmt.Errorf("could not decode signature: %w", err)
+	}
+	if !bytes.HasPrefix(b, []byte("SSHSIG")) {
+		return errors.New("signature is not an SSH signature")
+	}
+	blob := struct {
+		Version       uint32
+		PublicKey     string
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     string
+	}{}
+	err = ssh.Unmarshal(b[len("SSHSIG"):], &blob)
+	if err != nil {
+		return fmt.Errorf("could not decode signature: %w", err)
+	}
+	if blob.Namespace != sshSignatureNamespace {
+		return fmt.Errorf("signature has namespace %q instead of %q", blob.Namespace, sshSignatureNamespace)
+	}
+	pub, err := ssh.ParsePublicKey([]byte(blob.PublicKey))
+	if err != nil {
+		return fmt.Errorf("could not read public key of signature: %w", err)
+	}
+	trusted := false
+	for _, key := range keys {
+		if bytes.Equal(key.Marshal(), pub.Marshal()) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return fmt.Errorf("commit is signed with untrusted key %s", ssh.FingerprintSHA256(pub))
+	}
+	var h []byte
+	switch blob.HashAlgorithm {
+	case "sha512":
+		sum := sha512.Sum512(message)
+		h = sum[:]
+	case "sha256":
+		sum := sha256.Sum256(message)
+		h = sum[:]
+	default:
+		return fmt.Errorf("unsupported signature hash algorithm %q", blob.HashAlgorithm)
+	}
+	signedData := struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          string
+	}{
+		Namespace:     blob.Namespace,
+		Reserved:      blob.Reserved,
+		HashAlgorithm: blob.HashAlgorithm,
+		Hash:          string(h),
+	}
+	sig := &ssh.Signature{}
+	err = ssh.Unmarshal([]byte(blob.Signature), sig)
+	if err != nil {
+		return fmt.Errorf("could not decode signature: %w", err)
+	}
+	return pub.Verify(append([]byte("SSHSIG"), ssh.Marshal(signedData)...), sig)
+}
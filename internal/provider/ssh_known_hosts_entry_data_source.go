@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type SshKnownHostsEntryDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Host        types.String `tfsdk:"host"`
+	PublicKey   types.String `tfsdk:"public_key"`
+	Line        types.String `tfsdk:"line"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+}
+
+var _ datasource.DataSource = &SshKnownHostsEntryDataSource{}
+
+func NewSshKnownHostsEntryDataSource() datasource.DataSource {
+	return &SshKnownHostsEntryDataSource{}
+}
+
+// SshKnownHostsEntryDataSource formats a host key which is already trusted,
+// for example one published by the git host, as the known_hosts provider
+// setting expects it. The key is never fetched from the host itself.
+type SshKnownHostsEntryDataSource struct{}
+
+func (d *SshKnownHostsEntryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_known_hosts_entry"
+}
+
+func (d *SshKnownHostsEntryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "SSH known hosts entry data source, which converts a public host key to a line for the `known_hosts` provider setting and computes its SHA256 fingerprint. The host is not contacted.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"host": schema.StringAttribute{
+				Description: "Host name of the SSH server, optionally with a port, for example `github.com` or `git.example.com:2222`.",
+				Required:    true,
+			},
+			"public_key": schema.StringAttribute{
+				Description: "Public host key in the authorized keys format, for example `ssh-ed25519 AAAA...`.",
+				Required:    true,
+			},
+			"line": schema.StringAttribute{
+				Description: "Known hosts line of the host key.",
+				Computed:    true,
+			},
+			"fingerprint": schema.StringAttribute{
+				Description: "SHA256 fingerprint of the host key, for example `SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU`.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *SshKnownHostsEntryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data *SshKnownHostsEntryDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(data.PublicKey.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("public_key"), "Invalid Public Key", err.Error())
+		return
+	}
+	host := knownhosts.Normalize(data.Host.ValueString())
+	data.Line = types.StringValue(knownhosts.Line([]string{host}, key))
+	data.Fingerprint = types.StringValue(ssh.FingerprintSHA256(key))
+	data.ID = types.StringValue(host)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const telemetryName = "github.com/xenitab/terraform-provider-git"
+
+const (
+	operationClone  = "clone"
+	operationCommit = "commit"
+	operationPush   = "push"
+)
+
+// telemetryShutdowns flush and stop the exporters of all provider
+// configurations when the provider process exits.
+var (
+	telemetryShutdownsMu sync.Mutex
+	telemetryShutdowns   []func(context.Context) error
+)
+
+// ShutdownTelemetry exports the remaining traces and metrics. It is called
+// when Terraform stops the provider, as the provider is not notified when the
+// Terraform operation ends.
+func ShutdownTelemetry(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	telemetryShutdownsMu.Lock()
+	defer telemetryShutdownsMu.Unlock()
+	for _, shutdown := range telemetryShutdowns {
+		//nolint:errcheck // the provider is exiting
+		shutdown(ctx)
+	}
+	telemetryShutdowns = nil
+}
+
+// telemetry records a span and metrics for each git operation. The spans of
+// a provider configuration are children of a span which lasts until the
+// provider exits, so that the operations of a Terraform run are one trace.
+type telemetry struct {
+	tracer      trace.Tracer
+	root        trace.SpanContext
+	duration    syncfloat64.Histogram
+	transferred syncint64.Counter
+	retries     syncint64.Counter
+	failures    syncint64.Counter
+}
+
+func newTelemetry(ctx context.Context, t *Telemetry) (*telemetry, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	if t == nil {
+		return nil, diags
+	}
+	headers := map[string]string{}
+	if !t.Headers.IsNull() {
+		diags.Append(t.Headers.ElementsAs(ctx, &headers, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+	traceOpts := []otlptracehttp.Option{otlptracehttp.WithHeaders(headers)}
+	metricOpts := []otlpmetrichttp.Option{otlpmetrichttp.WithHeaders(headers)}
+	if endpoint := t.Endpoint.ValueString(); endpoint != "" {
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpoint(endpoint))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpoint(endpoint))
+	}
+	if t.Insecure.ValueBool() {
+		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+	}
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		diags.AddError("Telemetry Error", err.Error())
+		return nil, diags
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		diags.AddError("Telemetry Error", err.Error())
+		return nil, diags
+	}
+	res := resource.NewSchemaless(attribute.String("service.name", "terraform-provider-git"))
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(traceExporter), sdktrace.WithResource(res))
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)), sdkmetric.WithResource(res))
+
+	tracer := tracerProvider.Tracer(telemetryName)
+	_, root := tracer.Start(ctx, "terraform-provider-git")
+	telemetryShutdownsMu.Lock()
+	telemetryShutdowns = append(telemetryShutdowns, func(ctx context.Context) error {
+		root.End()
+		err := tracerProvider.Shutdown(ctx)
+		if err != nil {
+			return err
+		}
+		return meterProvider.Shutdown(ctx)
+	})
+	telemetryShutdownsMu.Unlock()
+
+	meter := meterProvider.Meter(telemetryName)
+	tel := &telemetry{tracer: tracer, root: root.SpanContext()}
+	tel.duration, err = meter.SyncFloat64().Histogram("git.operation.duration", instrument.WithUnit(unit.Milliseconds), instrument.WithDescription("Duration of git clones, commits and pushes."))
+	if err == nil {
+		tel.transferred, err = meter.SyncInt64().Counter("git.transfer.bytes", instrument.WithUnit(unit.Bytes), instrument.WithDescription("Bytes sent and received by git operations over HTTP."))
+	}
+	if err == nil {
+		tel.retries, err = meter.SyncInt64().Counter("git.push.retries", instrument.WithDescription("Pushes retried as the push was rejected or failed with a retryable error."))
+	}
+	if err == nil {
+		tel.failures, err = meter.SyncInt64().Counter("git.operation.failures", instrument.WithDescription("Failed git operations by error class."))
+	}
+	if err != nil {
+		diags.AddError("Telemetry Error", err.Error())
+		return nil, diags
+	}
+	return tel, diags
+}
+
+// record records the span and metrics of the operation.
+func (t *telemetry) record(ctx context.Context, repository string, op *gitOperation, paths []string, commit string, err error) {
+	if t == nil {
+		return
+	}
+	end := time.Now()
+	attrs := []attribute.KeyValue{
+		attribute.String("git.operation", op.name),
+		attribute.String("git.repository", repository),
+	}
+	transferred := atomic.LoadInt64(op.transferred)
+	t.duration.Record(ctx, float64(end.Sub(op.start).Milliseconds()), attrs...)
+	t.transferred.Add(ctx, transferred, attrs...)
+
+	ctx = trace.ContextWithSpanContext(ctx, t.root)
+	_, span := t.tracer.Start(ctx, "git."+op.name, trace.WithTimestamp(op.start), trace.WithAttributes(attrs...))
+	span.SetAttributes(
+		attribute.String("git.branch", op.branch),
+		attribute.StringSlice("git.paths", paths),
+		attribute.String("git.commit", commit),
+		attribute.Int64("git.transfer.bytes", transferred),
+	)
+	if err != nil {
+		class := classifyError(err)
+		t.failures.Add(ctx, 1, append(attrs, attribute.String("git.error.class", string(class)))...)
+		span.SetAttributes(attribute.String("git.error.class", string(class)))
+		span.SetStatus(codes.Error, string(class))
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// retry counts a push which is retried.
+func (t *telemetry) retry(ctx context.Context, repository, reason string) {
+	if t == nil {
+		return
+	}
+	t.retries.Add(ctx, 1, attribute.String("git.repository", repository), attribute.String("git.retry.reason", reason))
+}
+
+// gitOperation is a clone, commit or push which is recorded in the audit log
+// and telemetry.
+type gitOperation struct {
+	name        string
+	branch      string
+	start       time.Time
+	transferred *int64
+}
+
+// startOperation returns a context which counts the bytes transferred by the
+// operation over HTTP.
+func (prd *ProviderResourceData) startOperation(ctx context.Context, name, branch string) (context.Context, *gitOperation) {
+	op := &gitOperation{name: name, branch: branch, start: time.Now(), transferred: new(int64)}
+	return context.WithValue(ctx, transferContextKey{}, op.transferred), op
+}
+
+// endOperation records the outcome of the operation.
+func (prd *ProviderResourceData) endOperation(ctx context.Context, op *gitOperation, paths []string, commit string, err error) {
+	prd.audit(ctx, op.name, op.branch, paths, commit, op.start, err)
+	prd.telemetry.record(ctx, redactURLCredentials(prd.url), op, paths, commit, err)
+}
+
+type transferContextKey struct{}
+
+// countTransfer counts the bytes of the request and response bodies when the
+// request is made by an operation.
+func countTransfer(req *http.Request, transport http.RoundTripper) (*http.Response, error) {
+	counter, ok := req.Context().Value(transferContextKey{}).(*int64)
+	if !ok {
+		return transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req = req.Clone(req.Context())
+		req.Body = &countingReadCloser{ReadCloser: req.Body, counter: counter}
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingReadCloser{ReadCloser: resp.Body, counter: counter}
+	return resp, nil
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	counter *int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	atomic.AddInt64(c.counter, int64(n))
+	return n, err
+}
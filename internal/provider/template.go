@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// templateSuffix is the suffix of files which are rendered as templates.
+const templateSuffix = ".tmpl"
+
+// sourceIgnoreFile is the file in the root of the source directory with
+// gitignore style patterns of files which are not written.
+const sourceIgnoreFile = ".sourceignore"
+
+// renderTemplates returns the content of the files in the directory by their
+// slash separated path relative to the directory. Files with the .tmpl suffix
+// are rendered as Go templates with the variables and the suffix is removed,
+// while other files are copied as is. Files and directories matching the
+// patterns in the .sourceignore file or the gitignore style exclude patterns
+// are skipped, as is the .sourceignore file itself.
+func renderTemplates(dir string, vars map[string]string, exclude []string) (map[string]string, error) {
+	ignore, err := readSourceIgnore(dir)
+	if err != nil {
+		return nil, err
+	}
+	patterns := []gitignore.Pattern{}
+	for _, e := range append(ignore, exclude...) {
+		patterns = append(patterns, gitignore.ParsePattern(e, nil))
+	}
+	matcher := gitignore.NewMatcher(patterns)
+
+	files := map[string]string{}
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." || rel == sourceIgnoreFile {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if matcher.Match(strings.Split(rel, "/"), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		content := string(b)
+		name := rel
+		if strings.HasSuffix(rel, templateSuffix) {
+			name = strings.TrimSuffix(rel, templateSuffix)
+			content, err = renderTemplate(rel, content, vars)
+			if err != nil {
+				return err
+			}
+		}
+		if _, ok := files[name]; ok {
+			return fmt.Errorf("template %s and file %s are both rendered to %s", name+templateSuffix, name, name)
+		}
+		files[name] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// readSourceIgnore returns the patterns in the .sourceignore file of the
+// directory, skipping empty lines and comments. A missing file has no patterns.
+func readSourceIgnore(dir string) ([]string, error) {
+	b, err := os.ReadFile(filepath.Join(dir, sourceIgnoreFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	patterns := []string{}
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// renderTemplate renders the template with the variables, failing when the
+// template refers to a variable which is not set.
+func renderTemplate(name, text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, vars)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func init() {
+	// The go-git HTTP client is shared by the whole process, while the TLS
+	// configuration is specific to each provider configuration. The installed
+	// client selects the transport to use from the request context instead.
+	httpClient := githttp.NewClient(&http.Client{Transport: &contextTransport{}})
+	client.InstallProtocol("http", httpClient)
+	client.InstallProtocol("https", httpClient)
+}
+
+// azureDevOps records whether the provider configurations of the process use
+// Azure DevOps, as the capabilities go-git negotiates are global to the
+// process and cannot differ between provider configurations.
+var (
+	azureDevOpsMu  sync.Mutex
+	azureDevOps    *bool
+	errAzureDevOps = errors.New("azure_devops has to be set to the same value for all provider configurations, as the capability negotiation is shared by the provider process")
+)
+
+// setAzureDevOps makes sure that go-git does not treat the multi_ack
+// capabilities as unsupported when Azure DevOps is enabled. Azure DevOps
+// requires them and only full clones work without them being fully
+// implemented, so fetching into an existing repository has to be avoided with
+// Azure DevOps. An error is returned when another provider configuration of
+// the process has already been configured with a different value.
+func setAzureDevOps(enabled bool) error {
+	azureDevOpsMu.Lock()
+	defer azureDevOpsMu.Unlock()
+	if azureDevOps != nil {
+		if *azureDevOps != enabled {
+			return errAzureDevOps
+		}
+		return nil
+	}
+	azureDevOps = &enabled
+	if enabled {
+		transport.UnsupportedCapabilities = []capability.Capability{capability.ThinPack}
+	}
+	return nil
+}
+
+type transportContextKey struct{}
+
+// withHTTPTransport returns a context which makes git HTTP requests use the
+// given transport.
+func withHTTPTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	if transport == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, transportContextKey{}, transport)
+}
+
+type contextTransport struct{}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := req.Context().Value(transportContextKey{}).(http.RoundTripper); ok {
+		return countTransfer(req, transport)
+	}
+	return countTransfer(req, http.DefaultTransport)
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func tlsVersionNames() []string {
+	return []string{"1.0", "1.1", "1.2", "1.3"}
+}
+
+// cipherSuiteID returns the id of the cipher suite, which has to be one of the
+// secure cipher suites implemented by Go.
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, c := range tls.CipherSuites() {
+		if c.Name == name {
+			return c.ID, true
+		}
+	}
+	return 0, false
+}
+
+// headerTransport sets the configured User-Agent and headers on requests.
+type headerTransport struct {
+	transport http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.transport.RoundTrip(req)
+}
+
+func newHTTPTransport(h *Http) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if h == nil {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: h.InsecureSkipVerify.ValueBool(),
+	}
+	if v := h.TLSMinVersion.ValueString(); v != "" {
+		tlsConfig.MinVersion = tlsVersions[v]
+	}
+	for _, v := range h.TLSCipherSuites.Elements() {
+		name, ok := v.(types.String)
+		if !ok || name.IsUnknown() {
+			return nil, fmt.Errorf("cipher suites are not known")
+		}
+		id, ok := cipherSuiteID(name.ValueString())
+		if !ok {
+			return nil, fmt.Errorf("cipher suite %s is not supported", name.ValueString())
+		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+	ca, caFile := h.CertificateAuthority.ValueString(), h.CertificateAuthorityFile.ValueString()
+	if ca != "" || caFile != "" {
+		// The certificate authorities are added to the system trust store, so
+		// that public hosts can still be reached.
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, err
+		}
+		if rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if ca != "" && !rootCAs.AppendCertsFromPEM([]byte(ca)) {
+			return nil, fmt.Errorf("could not parse certificate authority")
+		}
+		if caFile != "" {
+			b, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read certificate authority file: %w", err)
+			}
+			if !rootCAs.AppendCertsFromPEM(b) {
+				return nil, fmt.Errorf("could not parse certificate authority file %s", caFile)
+			}
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	cert, key := h.ClientCertificate.ValueString(), h.ClientKey.ValueString()
+	if (cert == "") != (key == "") {
+		return nil, fmt.Errorf("client certificate and client key have to be set together")
+	}
+	if cert != "" {
+		clientCert, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if h.UserAgent.ValueString() == "" && len(h.Headers.Elements()) == 0 {
+		return transport, nil
+	}
+	headers := map[string]string{}
+	for k, v := range h.Headers.Elements() {
+		s, ok := v.(types.String)
+		if !ok || s.IsUnknown() {
+			return nil, fmt.Errorf("header %s is not known", k)
+		}
+		headers[k] = s.ValueString()
+	}
+	return &headerTransport{
+		transport: transport,
+		userAgent: h.UserAgent.ValueString(),
+		headers:   headers,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+var scpURLRegex = regexp.MustCompile(`^(?:([^@/]+)@)?([^:/]+):(.*)$`)
+
+// normalizeURL converts scp-style SSH urls such as git@github.com:org/repo.git
+// to the equivalent ssh:// url. Other urls are returned unchanged.
+func normalizeURL(rawURL string) string {
+	if strings.Contains(rawURL, "://") {
+		return rawURL
+	}
+	m := scpURLRegex.FindStringSubmatch(rawURL)
+	if m == nil {
+		return rawURL
+	}
+	user, host, path := m[1], m[2], strings.TrimPrefix(m[3], "/")
+	if user != "" {
+		return "ssh://" + user + "@" + host + "/" + path
+	}
+	return "ssh://" + host + "/" + path
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"net/url"
+	"strings"
+)
+
+// webURL returns the url where the file can be browsed at the commit. The
+// template is used when set, replacing the `{host}`, `{repository}`, `{branch}`,
+// `{commit}` and `{path}` placeholders. Otherwise the url is constructed for
+// repositories hosted on GitHub, GitLab, Bitbucket and Azure DevOps, and an
+// empty string is returned for other hosts.
+func webURL(template, repoURL, branch, commit, p string) string {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := u.Hostname()
+	repo := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	escapedPath := (&url.URL{Path: p}).EscapedPath()
+	if template != "" {
+		return strings.NewReplacer(
+			"{host}", host,
+			"{repository}", repo,
+			"{branch}", branch,
+			"{commit}", commit,
+			"{path}", escapedPath,
+		).Replace(template)
+	}
+
+	switch {
+	case host == "github.com":
+		return "https://github.com/" + repo + "/blob/" + commit + "/" + escapedPath
+	case strings.Contains(host, "gitlab"):
+		return "https://" + host + "/" + repo + "/-/blob/" + commit + "/" + escapedPath
+	case host == "bitbucket.org":
+		return "https://bitbucket.org/" + repo + "/src/" + commit + "/" + escapedPath
+	case host == "dev.azure.com", host == "ssh.dev.azure.com":
+		// SSH urls have the format v3/org/project/repo, while https urls
+		// have the format org/project/_git/repo.
+		parts := strings.Split(strings.TrimPrefix(repo, "v3/"), "/")
+		if len(parts) == 3 {
+			parts = []string{parts[0], parts[1], "_git", parts[2]}
+		}
+		if len(parts) != 4 || parts[2] != "_git" {
+			return ""
+		}
+		query := url.Values{"path": {"/" + p}, "version": {"GC" + commit}}
+		return "https://dev.azure.com/" + strings.Join(parts, "/") + "?" + query.Encode()
+	default:
+		return ""
+	}
+}
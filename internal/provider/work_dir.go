@@ -0,0 +1,14 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+)
+
+// workDirPath returns the directory of the cached working copy for the branch
+// of the repository.
+func workDirPath(workDir, repoURL, branch string) string {
+	h := sha256.Sum256([]byte(repoURL + "\x00" + branch))
+	return filepath.Join(workDir, fmt.Sprintf("%x", h[:16]))
+}
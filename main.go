@@ -18,6 +18,7 @@ func main() {
 		Address: "registry.terraform.io/xenitab/git",
 	}
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	provider.ShutdownTelemetry(context.Background())
 	if err != nil {
 		log.Fatal(err.Error())
 	}